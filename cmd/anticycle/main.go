@@ -10,28 +10,145 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/anticycle/anticycle/internal/pkg/lsp"
+	"github.com/anticycle/anticycle/internal/pkg/server"
 	"github.com/anticycle/anticycle/pkg/anticycle"
+	"github.com/anticycle/anticycle/pkg/model"
 	"github.com/anticycle/anticycle/pkg/serialize"
 )
 
 var version = "undefined"
 var build = "undefined"
 
+// options holds output settings set by command line flags.
+type options struct {
+	formatters []serialize.Formatter
+	all        bool
+	stability  bool
+	levels     bool
+	orphans    bool
+	allowed    []string
+	visibility bool
+	kinds      []string
+	baseline   string
+	config     string
+	today      time.Time
+	owners     bool
+	teams      bool
+	codeOwners string
+	output     string
+}
+
 const helpText = `Usage: anticycle [options] [directory]
+       anticycle -watch [-interval="1s"] [directory]
+       anticycle render -in="analysis.json" [-format="text"]
+       anticycle diff [-format="text"] old.json new.json
+       anticycle cache clean
+       anticycle lsp [-visibility] [-kinds=""] [-exclude=""]
+       anticycle serve [-addr=":8080"] [-kinds=""] [-exclude=""] [directory]
+       anticycle simulate -edit="move FILE DIR" [-edit=...] [directory]
+       anticycle split -pkg=NAME [-format=text] [directory]
 
   Anticycle is a tool for static code analysis which search for 
   dependency cycles. It scans recursively all source files and 
   parses theirs dependencies. Anticycle does not compile the code, 
   so it is ideal for searching for complex, difficult to debug cycles.
 
+Commands:
+  render               Renders analysis saved with -format=json into other
+                       format, without scanning the source code again.
+                       Takes -in="" option with path to the JSON file,
+                       or "-" to read from stdin, -format, -template
+                       and -o options.
+  diff                 Compares two analyses saved with -format=json and
+                       shows added, removed and changed cycles, packages
+                       which entered or left cycles and new imports between
+                       packages. Takes -format option, one of text, json
+                       or markdown. Imports are complete only for analyses
+                       saved with -all option.
+  cache clean          Removes cache of parsed files of all versions.
+  lsp                  Runs Language Server Protocol server over stdio,
+                       which publishes diagnostics on imports in cycles
+                       to the editor, see Editors below. Takes -kinds,
+                       -exclude, -excludeDefault and -visibility options.
+  serve                Scans the directory and serves the analysis over
+                       HTTP, see Server below. Takes -addr=":8080" option
+                       with address to listen on, -kinds, -exclude
+                       and -excludeDefault options.
+  simulate             Applies edits to packages in memory and compares
+                       cycles before and after edits, see Simulation below.
+                       Takes repeated -edit option, -format option, one of
+                       text or json, -kinds, -exclude and -excludeDefault.
+  split                Proposes how files of the package given with -pkg
+                       could be moved to a new package to break its cycles,
+                       see Split below. Takes -format option, one of text
+                       or json, -kinds, -exclude and -excludeDefault.
+
 Options:
   -all                 Output all packages, with and without cycles.
 
-  -format="text"       Output format. Available: text, json.
+  -format="text"       A comma-separated list of output formats, see
+                       Formats below.
+  -template=""         Path to Go text/template file which is executed
+                       with the analysis, see Templates below. It replaces
+                       the default format, unless -format is set.
+  -o=""                Base name of output files, e.g. -o=report writes
+                       report.txt and report.json for -format=text,json.
+                       Required for many formats. The default is stdout.
+  -baseline=""         Path to JSON output of previous run. Cycles are
+                       compared with the baseline and marked as new,
+                       existing or fixed.
+  -config=""           Path to configuration file. The default is
+                       .anticycle.json in the analyzed directory,
+                       if the file exists.
+  -today=""            Date compared with expiry dates of allowed cycles,
+                       e.g. "2018-06-01". The default is the current date,
+                       SOURCE_DATE_EPOCH sets only time of the output.
+
+  -stability           Output coupling metrics of all packages and imports
+                       which violate the Stable Dependencies Principle.
+
+  -levels              Output layers of packages, where cycles are collapsed
+                       into single node, and the longest dependency chain.
+
+  -orphans             Output packages which are not imported by any other
+                       package. Main and test packages are skipped.
+  -allowOrphans=""     A space-separated list of package names or paths
+                       which are allowed to be orphans, e.g. public library
+                       packages. Use with -orphans.
+
+  -visibility          Output imports of internal packages from outside
+                       of theirs parent directory.
+
+  -owners              Output owners of files with imports in cycles,
+                       read from CODEOWNERS file, see Owners below.
+  -teams               Output graph of imports aggregated by owners of
+                       files and cycles between owners.
+  -codeowners=""       Path to CODEOWNERS file. The default is looked up
+                       in the analyzed directory, .github, docs and .gitlab.
+
+  -kinds=""            A space-separated list of import kinds which should
+                       be analyzed and shown in the output. The default
+                       is all kinds. Available: stdlib, module, local,
+                       external, pseudo.
+
+  -cache="on"          Cache of parsed files, "on" or "off". Cache is kept
+                       in user cache directory, repeated runs parse only
+                       changed files.
+
+  -watch               Keeps running and outputs cycles introduced
+                       or resolved by changes of .go and go.mod files.
+  -interval="1s"       Interval of checking for changes with -watch.
 
   -exclude=""          A space-separated list of directories that should 
                        not be scanned. The list will be added to the 
@@ -39,10 +156,26 @@ Options:
   -excludeDefault=""   A space-separated list of directories that should 
                        not be scanned. The list will override the default.
   -showExcluded        Shows list of excluded directories.
+  -schema              Shows JSON Schema of the JSON output.
 
   -help                Shows this help text.
   -version             Shows version and build hash.
 
+Formats:
+{{formats}}
+
+Templates:
+  A template given with -template option is executed with the analysis,
+  the same as JSON output, e.g. {{len .Metadata.Cycles}} is a number
+  of cycles. Besides functions of text/template there are:
+  cycle                Joins packages of a cycle with arrows.
+  join                 Joins a list of strings with a separator.
+  rel                  Returns a path relative to a base directory,
+                       e.g. {{rel $.Root .Path}}.
+  count                Returns a number of elements, 0 for nil.
+  plural               Returns singular or plural form of a word,
+                       e.g. {{plural $n "cycle" "cycles"}}.
+
 Directory:
   An optional path to the analyzed project. If the directory is not 
   defined, the current working directory will be used.
@@ -55,10 +188,142 @@ Output:
   The JSON contains package names, 
   all dependencies in the package, a list of files belonging to 
   the package and their individual dependencies.
+  It is always a valid JSON, with schemaVersion, tool version, analyzed
+  root directory, timestamp and options of the run. Packages and files
+  are sorted by path. Set SOURCE_DATE_EPOCH environment variable to
+  a number of seconds to make the timestamp reproducible.
+
+  Formats mermaid and plantuml draw a diagram of packages grouped
+  by directories, where packages and imports in cycles are highlighted.
+  The diagram can be pasted into Markdown documents.
+
+  Format html produces a single, offline page with interactive graph
+  of packages, a list of cycles and files affected by each import.
+
+  Format junit produces JUnit XML report where each package is a test
+  case, which fails if the package is a part of a cycle. It always
+  includes all packages, as if -all option was set.
+
+  Format checkstyle produces Checkstyle XML report and format github
+  produces GitHub Actions workflow commands. Both report an error for
+  each import which creates a cycle, with file and line of the import.
+
+  Format markdown produces a summary table, a table of components
+  of packages in cycles and a collapsible section with files and
+  imports of each cycle, suitable for pull request comments. With
+  -baseline components are compared with the baseline.
+
+  Formats csv, graphml and gexf export graph of all packages, as if
+  -all option was set. Format csv is a list of imports between packages
+  with number of imports, cycle membership and importing files.
+  Formats graphml and gexf can be opened in graph tools like Gephi.
+
+  Each import in JSON has a kind: "stdlib" for standard library
+  found in GOROOT, "module" for the same module as importing package,
+  "local" for other module found in the analyzed directory, "external"
+  for third-party packages and "pseudo" for "C" and "unsafe".
+  Modules are read from go.mod files. Without GOROOT packages
+  of standard library are classified as "external".
 
   By default output will contain only cycles to reduce a clutter.
   If you want to print all packages you can use -all flag.
 
+  With -stability flag the output is extended with afferent (Ca)
+  and efferent (Ce) coupling and instability (I) of every package,
+  followed by imports where a stable package depends on a less stable
+  one, ordered by the instability delta.
+
+  With -levels flag the output is extended with a table of levels.
+  Level 0 packages import nothing from the analyzed directory, level N
+  packages depend only on lower levels. Packages in cycle are shown
+  in braces, e.g. {bar,baz}, and share a single level.
+
+  With -orphans flag the output is extended with a list of packages
+  which are not imported by any other scanned package. Packages listed
+  in -allowOrphans are reported separately as allowed orphans.
+
+  With -visibility flag the output is extended with imports which
+  violate the internal directory rule, it is a package importing
+  "a/b/internal/c" from outside of "a/b", with file and line of the import.
+  The code does not have to compile to find them.
+
+  Intentional imports can be acknowledged in the source code with
+  suppression directives, which take no part in cycles:
+    import "x/y" //anticycle:ignore reason
+  attached to an import, in the same line or in the line above, or
+    //anticycle:allow-cycle with=x/y reason
+  in any comment of the package before imports, e.g. in doc.go, which
+  suppresses all imports of package x/y. Suppressions and their
+  reasons are listed in the output, together with a warning about stale
+  suppressions, which do not match any import in a cycle.
+
+  Configuration file may list temporarily allowed cycles, e.g.
+    {"allowedCycles": [{"cycle": ["bar", "baz"], "owner": "team",
+                        "expires": "2018-06-01", "reason": "refactoring"}]}
+  Allowed cycles are reported as warnings until the expires date,
+  inclusive. When the list is not empty, expired cycles and cycles
+  which are not on the list fail the run with exit code 1, after
+  the output is written.
+
+  With -owners flag every file with an import in a cycle is followed
+  by its owners, read from CODEOWNERS file in GitHub or GitLab syntax,
+  so it is clear who should fix the cycle. Files without owners are
+  listed separately. With -teams flag imports are aggregated into
+  edges from owners of the importing file to owners of any file of
+  the imported package, and cycles between owners expose unhealthy
+  coupling of teams.
+
+  With -watch flag Anticycle outputs cycles found in the directory
+  and keeps parsed files in memory. Every interval it checks for
+  added, removed or modified .go and go.mod files, parses again only
+  changed directories and outputs a line per cycle which was introduced
+  or resolved, e.g.
+    15:04:05 introduced bar -> baz -> bar
+    15:04:07 resolved bar -> baz -> bar
+  Errors, e.g. syntax errors of files being edited, are sent to stderr
+  and watching continues. Options -kinds and -exclude are applied,
+  output options are ignored. Stop watching with Ctrl+C.
+
+  Editors:
+  Command lsp runs a language server for editors which support
+  Language Server Protocol. It scans the workspace when the editor
+  connects and again when a file is saved, and publishes an error
+  on each import which takes part in a cycle. With -visibility flag
+  imports which violate visibility of internal packages are published
+  as warnings. Suppression directives are applied.
+
+  Server:
+  Command serve keeps parsed files in memory and serves an interactive
+  graph of all packages on / and JSON API:
+    GET  /packages     All packages with cycles marked.
+    GET  /cycles       Analysis of packages in cycles, like -format=json.
+    GET  /why?from=a&to=b
+                       The shortest chain of imports from package a
+                       to package b, given by name or path, or the
+                       shortest cycle through a if b is the same.
+    GET  /metrics      Stability of packages, like -stability.
+    POST /rescan       Parses changed directories, responds like /cycles.
+
+  Simulation:
+  Command simulate answers what happens to cycles after a refactoring,
+  without changing the source code. Each -edit is one of:
+    move FILE DIR      Moves the file to package in the directory.
+    remove FILE IMPORT Removes the import from the file.
+    add FILE IMPORT    Adds the import to the file.
+  Paths are relative to the analyzed directory. A moved file keeps its
+  imports and importers of its symbols are not changed, add imports
+  to simulate it, e.g.
+    anticycle simulate -edit="move models/errors.go errs" \
+      -edit="add api/api.go app/errs" .
+
+  Split:
+  Command split parses whole files of the package to find which files use
+  identifiers declared in other files. For every import in a cycle, files
+  with the import and files which use them are proposed to be moved to
+  a new package, and the proposal is checked like by simulate, e.g.
+    anticycle split -pkg=models .
+  Identifiers are matched by name, without type checking.
+
   If Anticycle does not find any source files, it will exit 
   with code 0 without output in text format,
   or with empty structure in JSON format.
@@ -76,20 +341,45 @@ func trap(err error) {
 }
 
 func main() {
+	if len(os.Args) > 1 && commands[os.Args[1]] != nil {
+		output, err := commands[os.Args[1]](os.Args[2:])
+		trap(err)
+
+		err = printOutput(output)
+		trap(err)
+		os.Exit(0)
+	}
+
 	showHelp := flag.Bool("help", false, "Show help text.")
 	showVersion := flag.Bool("version", false, "Show version and build hash.")
 	showExcluded := flag.Bool("showExcluded", false, "Show list of excluded directories.")
+	showSchema := flag.Bool("schema", false, "Show JSON Schema of JSON output.")
 
 	setExclude := flag.String("exclude", "/", "A space-separated list of directories.")
 	setExcludeDefault := flag.String("excludeDefault", "/", "A space-separated list of directories.")
 
-	outputFormat := flag.String("format", "text", "Output format. Available: text,json.")
+	outputFormat := flag.String("format", "text", "A comma-separated list of output formats.")
+	setTemplate := flag.String("template", "", "Path to text/template file.")
+	setOutput := flag.String("o", "", "Base name of output files.")
 	outputAll := flag.Bool("all", false, "Output all packages, with and without cycles.")
+	outputStability := flag.Bool("stability", false, "Output stability metrics and SDP violations.")
+	outputLevels := flag.Bool("levels", false, "Output levels of packages and the longest chain.")
+	outputOrphans := flag.Bool("orphans", false, "Output packages not imported by any other package.")
+	setAllowOrphans := flag.String("allowOrphans", "", "A space-separated list of packages.")
+	outputVisibility := flag.Bool("visibility", false, "Output imports which violate internal packages visibility.")
+	setKinds := flag.String("kinds", "", "A space-separated list of import kinds.")
+	setBaseline := flag.String("baseline", "", "Path to JSON output of previous run.")
+	setConfig := flag.String("config", "", "Path to configuration file.")
+	setToday := flag.String("today", "", "Date compared with expiry dates of allowed cycles.")
+	outputOwners := flag.Bool("owners", false, "Output owners of imports in cycles, read from CODEOWNERS.")
+	outputTeams := flag.Bool("teams", false, "Output graph of imports aggregated by owners of files.")
+	setCodeOwners := flag.String("codeowners", "", "Path to CODEOWNERS file.")
+	setCache := flag.String("cache", "on", "Cache of parsed files, on or off.")
+	watchMode := flag.Bool("watch", false, "Watch for changes and output introduced or resolved cycles.")
+	setInterval := flag.String("interval", "1s", "Interval of checking for changes in watch mode.")
 	flag.Parse()
 
-	var err error
-
-	err = validateFormat(*outputFormat)
+	formatters, err := parseFormats(flag.CommandLine, *outputFormat, *setTemplate, *setOutput)
 	trap(err)
 
 	if *showHelp == true {
@@ -99,50 +389,87 @@ func main() {
 	}
 
 	if *showVersion == true {
-		err = printOutput(renderVersion(*outputFormat))
+		err = writeInfo(formatters, *setOutput, renderVersion)
+		trap(err)
+		os.Exit(0)
+	}
+
+	if *showSchema == true {
+		output, err := serialize.JSONSchema()
+		trap(err)
+
+		err = printOutput(output)
 		trap(err)
 		os.Exit(0)
 	}
 
 	excluded := excludedDirs(*setExclude, *setExcludeDefault)
 	if *showExcluded == true {
-		err = printOutput(renderExcluded(*outputFormat, excluded))
+		err = writeInfo(formatters, *setOutput, func(formatter serialize.Formatter) string {
+			return renderExcluded(formatter, excluded)
+		})
 		trap(err)
 		os.Exit(0)
 	}
 
+	err = enableCache(*setCache)
+	trap(err)
+
 	dir := rootDir(flag.Args())
-	output, err := findCycles(dir, excluded, *outputFormat, *outputAll)
+	if *watchMode == true {
+		interval, err := time.ParseDuration(*setInterval)
+		if err != nil || interval <= 0 {
+			trap(fmt.Errorf("-interval='%v' is not a valid duration, try e.g. '500ms' or '2s'", *setInterval))
+		}
+		err = watch(dir, excluded, splitList(*setKinds), interval)
+		trap(err)
+	}
+
+	today, err := parseToday(*setToday)
 	trap(err)
 
-	err = printOutput(output)
+	opts := options{
+		formatters: formatters,
+		all:        *outputAll,
+		stability:  *outputStability,
+		levels:     *outputLevels,
+		orphans:    *outputOrphans,
+		allowed:    splitList(*setAllowOrphans),
+		visibility: *outputVisibility,
+		kinds:      splitList(*setKinds),
+		baseline:   *setBaseline,
+		config:     *setConfig,
+		today:      today,
+		owners:     *outputOwners,
+		teams:      *outputTeams,
+		codeOwners: *setCodeOwners,
+		output:     *setOutput,
+	}
+	err = findCycles(dir, excluded, opts)
 	trap(err)
 
 	os.Exit(0)
 }
 
-func validateFormat(format string) (err error) {
-	if !strings.EqualFold(format, "json") && !strings.EqualFold(format, "text") {
-		err = fmt.Errorf("-format='%v' is not available, try 'text' or 'json'", format)
-	}
-	return err
-}
-
 func renderHelp() string {
 	// TODO(pawelzny) support for JSON output format.
-	return helpText
+	var formats strings.Builder
+	for _, formatter := range serialize.Formatters() {
+		formats.WriteString(fmt.Sprintf("  %-21s%s\n", formatter.Name(), formatter.Description()))
+	}
+	return strings.Replace(helpText, "{{formats}}", strings.TrimRight(formats.String(), "\n"), 1)
 }
 
-func renderVersion(format string) string {
-	if strings.ToLower(format) == "json" {
+func renderVersion(formatter serialize.Formatter) string {
+	if isJSONFormat(formatter) {
 		verJSON, _ := json.Marshal(map[string]string{"version": version, "build": build})
 		return string(verJSON)
 	}
 	return fmt.Sprintf("Anticycle version %s, build %s", version, build)
 }
 
-func renderExcluded(format string, excluded []string) string {
-	if strings.ToLower(format) == "json" {
+func renderExcluded(formatter serialize.Formatter, excluded []string) string {
+	if isJSONFormat(formatter) {
 		exJSON, _ := json.Marshal(map[string][]string{"excluded": excluded})
 		return string(exJSON)
 	}
@@ -168,6 +495,14 @@ func excludedDirs(setExclude, setExcludeDefault string) (exclude []string) {
 	return anticycle.ExcludeDirs(exclude)
 }
 
+func splitList(list string) []string {
+	list = strings.Trim(list, "\"' ")
+	if list == "" {
+		return []string{}
+	}
+	return strings.Fields(list)
+}
+
 func rootDir(args []string) string {
 	if len(args) > 0 {
 		return path.Clean(args[0])
@@ -175,20 +510,683 @@ func rootDir(args []string) string {
 	return "."
 }
 
-func findCycles(dir string, excluded []string, format string, all bool) (output string, err error) {
-	cycles, err := anticycle.Collect(dir, excluded, all)
+func findCycles(dir string, excluded []string, opts options) (err error) {
+	packages, err := anticycle.Fetch(dir, excluded)
+	if err != nil {
+		return err
+	}
+	if len(opts.kinds) > 0 {
+		packages, err = anticycle.FilterKinds(packages, opts.kinds)
+		if err != nil {
+			return err
+		}
+	}
+	suppressions := anticycle.Suppress(packages)
+	// Keep all packages, because metrics must be computed on complete graph.
+	cycles, err := anticycle.FindCycles(packages)
+	if err != nil {
+		return err
+	}
+
+	reports := &model.Analysis{
+		Tool: &model.Tool{Name: "anticycle", Version: version, Build: build},
+		Root: dir,
+		Options: &model.Options{
+			All:          opts.all,
+			Excluded:     excluded,
+			Kinds:        opts.kinds,
+			Stability:    opts.stability,
+			Levels:       opts.levels,
+			Orphans:      opts.orphans,
+			AllowOrphans: opts.allowed,
+			Visibility:   opts.visibility,
+			Baseline:     opts.baseline,
+			Owners:       opts.owners,
+			Teams:        opts.teams,
+		},
+	}
+	if len(suppressions.Active) > 0 || len(suppressions.Stale) > 0 {
+		reports.Suppressions = suppressions
+	}
+	if opts.stability {
+		reports.Stability = anticycle.Stability(cycles)
+	}
+	if opts.levels {
+		reports.Levels = anticycle.Levels(cycles)
+	}
+	if opts.orphans {
+		reports.Orphans = anticycle.Orphans(cycles, opts.allowed)
+	}
+	if opts.visibility {
+		reports.Visibility = anticycle.Visibility(cycles)
+	}
+	if opts.owners || opts.teams {
+		codeOwners, err := loadCodeOwners(opts.codeOwners, dir)
+		if err != nil {
+			return err
+		}
+		reports.Owners = anticycle.Owners(cycles, codeOwners, opts.teams)
+	}
+	now, err := anticycle.Timestamp()
+	if err != nil {
+		return err
+	}
+	reports.Timestamp = now.Format(time.RFC3339)
+	configPath, config, err := loadConfig(opts.config, dir)
+	if err != nil {
+		return err
+	}
+	var baseline *model.Analysis
+	if opts.baseline != "" {
+		baseline, err = loadBaseline(opts.baseline)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Filtering modifies packages, so formats which need all packages are written first.
+	affectedOnly := make([]serialize.Formatter, 0, len(opts.formatters))
+	allPackages := make([]serialize.Formatter, 0, len(opts.formatters))
+	for _, formatter := range opts.formatters {
+		if opts.all || serialize.NeedsAllPackages(formatter) {
+			allPackages = append(allPackages, formatter)
+		} else {
+			affectedOnly = append(affectedOnly, formatter)
+		}
+	}
+	var analysis *model.Analysis
+	if len(allPackages) > 0 {
+		analysis = analyze(cycles, reports, baseline, config, opts.today)
+		err = writeOutputs(allPackages, analysis, opts.output)
+		if err != nil {
+			return err
+		}
+	}
+	if len(affectedOnly) > 0 {
+		cycles = anticycle.OnlyAffected(cycles)
+		analysis = analyze(cycles, reports, baseline, config, opts.today)
+		err = writeOutputs(affectedOnly, analysis, opts.output)
+		if err != nil {
+			return err
+		}
+	}
+	if allowlist := analysis.Allowlist; allowlist != nil && len(allowlist.Expired)+len(allowlist.NotAllowed) > 0 {
+		return fmt.Errorf("found %d expired and %d not allowed cycles, see allowedCycles in '%v'",
+			len(allowlist.Expired), len(allowlist.NotAllowed), configPath)
+	}
+	return nil
+}
+
+// watch outputs cycles of the directory and then checks for changes every interval
+// and outputs cycles which were introduced or resolved. Errors of a check,
+// e.g. syntax errors in files being edited, are sent to stderr once
+// and watching continues. It returns only if the first check fails.
+func watch(dir string, excluded, kinds []string, interval time.Duration) error {
+	watcher := anticycle.NewWatcher(dir, excluded, kinds)
+	report, err := watcher.Check()
+	if err != nil {
+		return err
+	}
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Watching %s, found %d cycles\n", dir, len(report.New)))
+	for _, cycle := range report.New {
+		output.WriteString(fmt.Sprintf("%s\n", strings.Join(cycle, " -> ")))
+	}
+	if err = printOutput(strings.TrimRight(output.String(), "\n")); err != nil {
+		return err
+	}
+
+	lastErr := ""
+	for range time.Tick(interval) {
+		report, err := watcher.Check()
+		if err != nil {
+			if err.Error() != lastErr {
+				lastErr = err.Error()
+				fmt.Fprintln(os.Stderr, err)
+			}
+			continue
+		}
+		lastErr = ""
+		if report != nil {
+			if err = printOutput(serialize.WatchToTxt(report, time.Now())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// analyze computes metadata of packages and attaches reports computed upfront.
+func analyze(packages []*model.Pkg, reports, baseline *model.Analysis, config *model.Config, today time.Time) *model.Analysis {
+	analysis := anticycle.Analyze(packages)
+	analysis.Tool = reports.Tool
+	analysis.Root = reports.Root
+	analysis.Timestamp = reports.Timestamp
+	analysis.Options = reports.Options
+	analysis.Stability = reports.Stability
+	analysis.Levels = reports.Levels
+	analysis.Orphans = reports.Orphans
+	analysis.Visibility = reports.Visibility
+	analysis.Suppressions = reports.Suppressions
+	analysis.Owners = reports.Owners
+	if baseline != nil {
+		analysis.Baseline = anticycle.Baseline(analysis, baseline)
+	}
+	if config != nil && len(config.AllowedCycles) > 0 {
+		analysis.Allowlist = anticycle.Allowlist(analysis, config.AllowedCycles, today)
+	}
+	return analysis
+}
+
+// parseToday reads date compared with expiry dates of allowed cycles.
+// Current time is used if the date is empty. Expiry does not follow
+// SOURCE_DATE_EPOCH, which is often fixed in the past by reproducible builds.
+func parseToday(setToday string) (time.Time, error) {
+	if setToday == "" {
+		return time.Now().UTC(), nil
+	}
+	today, err := time.Parse("2006-01-02", setToday)
+	if err != nil {
+		return today, fmt.Errorf("-today='%v' is not a valid date, try e.g. '2018-06-01'", setToday)
+	}
+	return today, nil
+}
+
+// defaultConfig is a name of configuration file looked up in the analyzed directory.
+const defaultConfig = ".anticycle.json"
+
+// loadConfig reads configuration from given path, or from .anticycle.json
+// in the analyzed directory if path is empty. Missing default file is not an error.
+func loadConfig(path, dir string) (string, *model.Config, error) {
+	if path == "" {
+		path = filepath.Join(dir, defaultConfig)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path, nil, nil
+		}
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return path, nil, err
+	}
+	config, err := anticycle.ParseConfig(data)
+	if err != nil {
+		return path, nil, fmt.Errorf("-config='%v' is not a valid configuration: %v", path, err)
+	}
+	return path, config, nil
+}
+
+// loadCodeOwners reads CODEOWNERS from given path, or looks it up in the analyzed
+// directory like GitHub and GitLab do. Patterns are relative to the directory
+// of the file, or to its parent if the file is in .github, .gitlab or docs.
+func loadCodeOwners(path, dir string) (*model.CodeOwners, error) {
+	if path == "" {
+		for _, location := range anticycle.CodeOwnersLocations {
+			candidate := filepath.Join(dir, filepath.FromSlash(location))
+			if _, err := os.Stat(candidate); err == nil {
+				path = candidate
+				break
+			}
+		}
+		if path == "" {
+			return nil, fmt.Errorf("CODEOWNERS file is not found in '%v', try -codeowners option", dir)
+		}
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	codeOwners, err := anticycle.ParseCodeOwners(data)
+	if err != nil {
+		return nil, fmt.Errorf("-codeowners='%v' is not valid: %v", path, err)
+	}
+	codeOwners.File = path
+	codeOwners.Root = filepath.Dir(path)
+	if model.SliceContains([]string{".github", ".gitlab", "docs"}, filepath.Base(codeOwners.Root)) {
+		codeOwners.Root = filepath.Dir(codeOwners.Root)
+	}
+	return codeOwners, nil
+}
+
+// parseFormats finds formatters of a comma-separated list of formats and
+// of the template. The template replaces the default format, unless -format
+// is set explicitly. Many formats can be written only to files.
+func parseFormats(flags *flag.FlagSet, format, templatePath, output string) ([]serialize.Formatter, error) {
+	formatters := make([]serialize.Formatter, 0)
+	if templatePath == "" || isFlagSet(flags, "format") {
+		for _, name := range strings.Split(format, ",") {
+			formatter := serialize.Lookup(strings.TrimSpace(name))
+			if formatter == nil {
+				return nil, fmt.Errorf("-format='%v' is not available, try one of '%v'",
+					strings.TrimSpace(name), strings.Join(formatNames(), "', '"))
+			}
+			formatters = append(formatters, formatter)
+		}
+	}
+	if templatePath != "" {
+		formatter, err := parseTemplate(templatePath)
+		if err != nil {
+			return nil, err
+		}
+		formatters = append(formatters, formatter)
+	}
+	if len(formatters) > 1 && output == "" {
+		if templatePath != "" {
+			return nil, fmt.Errorf("-format='%v' with -template='%v' has many formats, use -o option to write them into files", format, templatePath)
+		}
+		return nil, fmt.Errorf("-format='%v' has many formats, use -o option to write them into files", format)
+	}
+	return formatters, nil
+}
+
+// parseTemplate reads user-supplied text/template. Extension of the output file
+// is taken from the template name, e.g. slack.md.tmpl is written as .md file.
+func parseTemplate(path string) (serialize.Formatter, error) {
+	text, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("-template='%v' can not be read: %v", path, err)
+	}
+	name := filepath.Base(path)
+	extension := strings.TrimPrefix(filepath.Ext(strings.TrimSuffix(name, filepath.Ext(name))), ".")
+	if extension == "" {
+		extension = "txt"
+	}
+	formatter, err := serialize.NewTemplateFormatter("template", extension, string(text))
+	if err != nil {
+		return nil, fmt.Errorf("-template='%v' is not a valid template: %v", path, err)
+	}
+	return formatter, nil
+}
+
+func isFlagSet(flags *flag.FlagSet, name string) (set bool) {
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func formatNames() []string {
+	names := make([]string, 0)
+	for _, formatter := range serialize.Formatters() {
+		names = append(names, formatter.Name())
+	}
+	return names
+}
+
+// writeOutputs writes analysis in each format to stdout, or to a file
+// named after output with extension of the format.
+func writeOutputs(formatters []serialize.Formatter, analysis *model.Analysis, output string) error {
+	for _, formatter := range formatters {
+		formatter := formatter
+		err := writeFormat(formatter, output, func(w io.Writer) error {
+			return formatter.Render(w, analysis)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeInfo writes information about the tool, like version, in each format
+// like writeOutputs. Formats other than JSON get the text version.
+func writeInfo(formatters []serialize.Formatter, output string, render func(formatter serialize.Formatter) string) error {
+	for _, formatter := range formatters {
+		text := render(formatter)
+		err := writeFormat(formatter, output, func(w io.Writer) (err error) {
+			if text != "" {
+				_, err = fmt.Fprintln(w, text)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFormat writes output of the formatter to stdout, or to a file named
+// after output with extension of the format.
+func writeFormat(formatter serialize.Formatter, output string, write func(w io.Writer) error) error {
+	if output == "" {
+		return write(os.Stdout)
+	}
+	file, err := os.Create(output + "." + formatter.Extension())
+	if err != nil {
+		return err
+	}
+	err = write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func isJSONFormat(formatter serialize.Formatter) bool {
+	return strings.EqualFold(formatter.Name(), "json")
+}
+
+// commands are run when the first argument is a name of the command.
+var commands = map[string]func(args []string) (string, error){
+	"render":   render,
+	"diff":     diff,
+	"cache":    cache,
+	"lsp":      languageServer,
+	"serve":    serve,
+	"simulate": simulate,
+	"split":    split,
+}
+
+// cacheRoot returns directory of caches of all versions of anticycle.
+func cacheRoot() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "anticycle"), nil
+}
+
+// enableCache sets cache directory of this version of anticycle and of JSON schema,
+// because parsed files hold imports of the schema. Cache is silently disabled
+// if there is no user cache directory.
+func enableCache(setCache string) error {
+	switch setCache {
+	case "on":
+		root, err := cacheRoot()
+		if err == nil {
+			anticycle.CacheDir = filepath.Join(root, fmt.Sprintf("%v-%v-v%d", version, build, model.SchemaVersion))
+		}
+		return nil
+	case "off":
+		anticycle.CacheDir = ""
+		return nil
+	}
+	return fmt.Errorf("-cache='%v' is not available, try one of 'on', 'off'", setCache)
+}
+
+func cache(args []string) (output string, err error) {
+	if len(args) != 1 || args[0] != "clean" {
+		return output, fmt.Errorf("cache requires 'clean' subcommand")
+	}
+	root, err := cacheRoot()
+	if err != nil {
+		return output, err
+	}
+	if err = os.RemoveAll(root); err != nil {
+		return output, err
+	}
+	return fmt.Sprintf("Removed cache in %s", root), nil
+}
+
+// languageServer speaks Language Server Protocol over stdio until the client exits.
+func languageServer(args []string) (output string, err error) {
+	flags := flag.NewFlagSet("lsp", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	setExclude := flags.String("exclude", "/", "A space-separated list of directories.")
+	setExcludeDefault := flags.String("excludeDefault", "/", "A space-separated list of directories.")
+	setKinds := flags.String("kinds", "", "A space-separated list of import kinds.")
+	visibility := flags.Bool("visibility", false, "Report imports which violate internal packages visibility.")
+	if err = flags.Parse(args); err != nil {
+		return output, err
+	}
+	kinds := splitList(*setKinds)
+	if _, err = anticycle.FilterKinds(nil, kinds); err != nil {
+		return output, err
+	}
+
+	server := lsp.NewServer(excludedDirs(*setExclude, *setExcludeDefault), kinds, *visibility)
+	server.Version = version
+	return output, server.Serve(os.Stdin, os.Stdout)
+}
+
+// serve scans the directory and serves the analysis over HTTP until the process is stopped.
+func serve(args []string) (output string, err error) {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	addr := flags.String("addr", ":8080", "Address to listen on.")
+	setExclude := flags.String("exclude", "/", "A space-separated list of directories.")
+	setExcludeDefault := flags.String("excludeDefault", "/", "A space-separated list of directories.")
+	setKinds := flags.String("kinds", "", "A space-separated list of import kinds.")
+	if err = flags.Parse(args); err != nil {
+		return output, err
+	}
+	kinds := splitList(*setKinds)
+	if _, err = anticycle.FilterKinds(nil, kinds); err != nil {
+		return output, err
+	}
+
+	dir := rootDir(flags.Args())
+	srv := server.NewServer(dir, excludedDirs(*setExclude, *setExcludeDefault), kinds)
+	srv.Tool = &model.Tool{Name: "anticycle", Version: version, Build: build}
+	if err = srv.Rescan(); err != nil {
+		return output, err
+	}
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return output, fmt.Errorf("-addr='%v' can not be used: %v", *addr, err)
+	}
+	fmt.Fprintf(os.Stdout, "Serving analysis of %s on http://%s\n", dir, listener.Addr())
+	return output, http.Serve(listener, srv)
+}
+
+// editList collects values of repeated -edit option.
+type editList []string
+
+func (l *editList) String() string { return strings.Join(*l, ", ") }
+
+func (l *editList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// simulate applies edits to packages in memory and compares cycles before and after edits.
+func simulate(args []string) (output string, err error) {
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	var setEdits editList
+	flags.Var(&setEdits, "edit", "Edit of the source code, may be repeated.")
+	format := flags.String("format", "text", "Output format.")
+	setExclude := flags.String("exclude", "/", "A space-separated list of directories.")
+	setExcludeDefault := flags.String("excludeDefault", "/", "A space-separated list of directories.")
+	setKinds := flags.String("kinds", "", "A space-separated list of import kinds.")
+	if err = flags.Parse(args); err != nil {
+		return output, err
+	}
+	if !model.SliceContains(simulateFormats, strings.ToLower(*format)) {
+		return output, fmt.Errorf("-format='%v' is not available for simulate, try one of '%v'",
+			*format, strings.Join(simulateFormats, "', '"))
+	}
+	if len(setEdits) == 0 {
+		return output, fmt.Errorf("simulate requires at least one -edit option, e.g. -edit='move models/errors.go errs'")
+	}
+	edits := make([]*model.Edit, 0, len(setEdits))
+	for _, setEdit := range setEdits {
+		edit, err := anticycle.ParseEdit(setEdit)
+		if err != nil {
+			return output, err
+		}
+		edits = append(edits, edit)
+	}
+
+	dir := rootDir(flags.Args())
+	excluded := excludedDirs(*setExclude, *setExcludeDefault)
+	kinds := splitList(*setKinds)
+	before, err := simulationCycles(dir, excluded, kinds, nil)
+	if err != nil {
+		return output, err
+	}
+	after, err := simulationCycles(dir, excluded, kinds, edits)
 	if err != nil {
 		return output, err
 	}
-	analysis := anticycle.Analyze(cycles)
+	report := anticycle.Simulation(before, after, edits)
 
-	switch strings.ToLower(format) {
+	switch strings.ToLower(*format) {
 	case "json":
-		output, err = serialize.ToJSON(analysis)
+		output, err = serialize.SimulationToJSON(report)
 	case "text":
-		output, err = serialize.ToTxt(analysis)
+		output, err = serialize.SimulationToTxt(report)
+	}
+	return output, err
+}
+
+// simulationCycles analyzes cycles of the directory with edits applied before cycles are found.
+func simulationCycles(dir string, excluded, kinds []string, edits []*model.Edit) (*model.Analysis, error) {
+	packages, err := anticycle.Fetch(dir, excluded)
+	if err != nil {
+		return nil, err
+	}
+	packages, err = anticycle.ApplyEdits(packages, dir, edits)
+	if err != nil {
+		return nil, err
+	}
+	if len(kinds) > 0 {
+		packages, err = anticycle.FilterKinds(packages, kinds)
+		if err != nil {
+			return nil, err
+		}
+	}
+	anticycle.Suppress(packages)
+	cycles, err := anticycle.FindCycles(packages)
+	if err != nil {
+		return nil, err
+	}
+	return anticycle.Analyze(anticycle.OnlyAffected(cycles)), nil
+}
+
+// split proposes how files of a package could be moved to a new package to break its cycles.
+func split(args []string) (output string, err error) {
+	flags := flag.NewFlagSet("split", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	setPkg := flags.String("pkg", "", "Name or path of the split package.")
+	format := flags.String("format", "text", "Output format.")
+	setExclude := flags.String("exclude", "/", "A space-separated list of directories.")
+	setExcludeDefault := flags.String("excludeDefault", "/", "A space-separated list of directories.")
+	setKinds := flags.String("kinds", "", "A space-separated list of import kinds.")
+	if err = flags.Parse(args); err != nil {
+		return output, err
+	}
+	if !model.SliceContains(simulateFormats, strings.ToLower(*format)) {
+		return output, fmt.Errorf("-format='%v' is not available for split, try one of '%v'",
+			*format, strings.Join(simulateFormats, "', '"))
+	}
+	if *setPkg == "" {
+		return output, fmt.Errorf("split requires -pkg option with name or path of a package, e.g. -pkg=models")
+	}
+
+	dir := rootDir(flags.Args())
+	packages, err := anticycle.Fetch(dir, excludedDirs(*setExclude, *setExcludeDefault))
+	if err != nil {
+		return output, err
+	}
+	if kinds := splitList(*setKinds); len(kinds) > 0 {
+		packages, err = anticycle.FilterKinds(packages, kinds)
+		if err != nil {
+			return output, err
+		}
+	}
+	anticycle.Suppress(packages)
+	plan, err := anticycle.Split(packages, dir, *setPkg)
+	if err != nil {
+		return output, err
 	}
 
+	switch strings.ToLower(*format) {
+	case "json":
+		output, err = serialize.SplitToJSON(plan)
+	case "text":
+		output, err = serialize.SplitToTxt(plan)
+	}
+	return output, err
+}
+
+var simulateFormats = []string{"text", "json"}
+
+var diffFormats = []string{"text", "json", "markdown"}
+
+func loadBaseline(path string) (*model.Analysis, error) {
+	baseline, err := readAnalysis(path)
+	if err != nil {
+		return nil, fmt.Errorf("-baseline='%v' is not a JSON output of anticycle: %v", path, err)
+	}
+	return baseline, nil
+}
+
+// readAnalysis reads JSON output of anticycle from the file, or from stdin if path is "-".
+func readAnalysis(path string) (*model.Analysis, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return serialize.FromJSON(string(data))
+}
+
+func render(args []string) (output string, err error) {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	input := flags.String("in", "", "Path to JSON output of anticycle.")
+	format := flags.String("format", "text", "Output format.")
+	templatePath := flags.String("template", "", "Path to text/template file.")
+	outputFile := flags.String("o", "", "Base name of output files.")
+	if err = flags.Parse(args); err != nil {
+		return output, err
+	}
+	formatters, err := parseFormats(flags, *format, *templatePath, *outputFile)
+	if err != nil {
+		return output, err
+	}
+	if *input == "" {
+		return output, fmt.Errorf("render requires -in option with path to JSON output of anticycle")
+	}
+
+	analysis, err := readAnalysis(*input)
+	if err != nil {
+		return output, fmt.Errorf("-in='%v' is not a JSON output of anticycle: %v", *input, err)
+	}
+	return output, writeOutputs(formatters, analysis, *outputFile)
+}
+
+func diff(args []string) (output string, err error) {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	format := flags.String("format", "text", "Output format.")
+	if err = flags.Parse(args); err != nil {
+		return output, err
+	}
+	if !model.SliceContains(diffFormats, strings.ToLower(*format)) {
+		return output, fmt.Errorf("-format='%v' is not available for diff, try one of '%v'",
+			*format, strings.Join(diffFormats, "', '"))
+	}
+	if flags.NArg() != 2 {
+		return output, fmt.Errorf("diff requires paths to old and new JSON output of anticycle")
+	}
+
+	analyses := make([]*model.Analysis, 0, 2)
+	for _, path := range flags.Args() {
+		analysis, err := readAnalysis(path)
+		if err != nil {
+			return output, fmt.Errorf("'%v' is not a JSON output of anticycle: %v", path, err)
+		}
+		analyses = append(analyses, analysis)
+	}
+	report := anticycle.Diff(analyses[0], analyses[1])
+
+	switch strings.ToLower(*format) {
+	case "json":
+		output, err = serialize.DiffToJSON(report)
+	case "text":
+		output, err = serialize.DiffToTxt(report)
+	case "markdown":
+		output, err = serialize.DiffToMarkdown(report)
+	}
 	return output, err
 }
 
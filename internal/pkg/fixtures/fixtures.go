@@ -0,0 +1,26 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+// Package fixtures writes source files of projects generated by tests.
+package fixtures
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// WriteFile writes data into the file of the directory. Missing directories are created.
+func WriteFile(dir, filename, data string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, filename), []byte(data), 0600)
+}
+
+// WriteSource writes source of the package into a file named after the last
+// element of package path, e.g. dir/app/bar/bar.go for package app/bar.
+func WriteSource(dir, pkg, data string) error {
+	return WriteFile(filepath.Join(dir, pkg), filepath.Base(pkg)+".go", data)
+}
@@ -0,0 +1,176 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// JSON-RPC error codes used by the server.
+const (
+	codeParseError           = -32700
+	codeMethodNotFound       = -32601
+	codeServerNotInitialized = -32002
+)
+
+// Diagnostic severities.
+const (
+	severityError   = 1
+	severityWarning = 2
+)
+
+// messageError is a type of window/logMessage notification.
+const messageError = 1
+
+type (
+	// request is an incoming request or notification, notifications have no ID.
+	request struct {
+		ID     *json.RawMessage `json:"id"`
+		Method string           `json:"method"`
+		Params json.RawMessage  `json:"params"`
+	}
+
+	// response holds either result, which is null if there is no result, or error.
+	response struct {
+		JSONRPC string           `json:"jsonrpc"`
+		ID      *json.RawMessage `json:"id"`
+		Result  *json.RawMessage `json:"result,omitempty"`
+		Error   *responseError   `json:"error,omitempty"`
+	}
+
+	responseError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+
+	notification struct {
+		JSONRPC string      `json:"jsonrpc"`
+		Method  string      `json:"method"`
+		Params  interface{} `json:"params"`
+	}
+
+	initializeParams struct {
+		RootURI  string `json:"rootUri"`
+		RootPath string `json:"rootPath"`
+	}
+
+	initializeResult struct {
+		Capabilities serverCapabilities `json:"capabilities"`
+		ServerInfo   serverInfo         `json:"serverInfo"`
+	}
+
+	serverCapabilities struct {
+		TextDocumentSync textDocumentSyncOptions `json:"textDocumentSync"`
+	}
+
+	textDocumentSyncOptions struct {
+		OpenClose bool        `json:"openClose"`
+		Save      saveOptions `json:"save"`
+	}
+
+	saveOptions struct {
+		IncludeText bool `json:"includeText"`
+	}
+
+	serverInfo struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	position struct {
+		Line      int `json:"line"`
+		Character int `json:"character"`
+	}
+
+	textRange struct {
+		Start position `json:"start"`
+		End   position `json:"end"`
+	}
+
+	diagnostic struct {
+		Range    textRange `json:"range"`
+		Severity int       `json:"severity"`
+		Source   string    `json:"source"`
+		Message  string    `json:"message"`
+	}
+
+	publishDiagnosticsParams struct {
+		URI         string        `json:"uri"`
+		Diagnostics []*diagnostic `json:"diagnostics"`
+	}
+
+	logMessageParams struct {
+		Type    int    `json:"type"`
+		Message string `json:"message"`
+	}
+)
+
+// readMessage reads content of a message framed with Content-Length header.
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("invalid header '%v'", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(line[:colon]), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(line[colon+1:]))
+			if err != nil || length < 0 {
+				return nil, fmt.Errorf("invalid header '%v'", line)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("missing Content-Length header")
+	}
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// writeMessage writes the message as JSON framed with Content-Length header.
+func writeMessage(w io.Writer, message interface{}) error {
+	content, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(content), content)
+	return err
+}
+
+// uriToPath converts file URI to a path, other URIs are not supported.
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("URI '%v' is not a file URI", uri)
+	}
+	return filepath.FromSlash(u.Path), nil
+}
+
+// pathToURI converts absolute path to file URI.
+func pathToURI(path string) string {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return u.String()
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteMessage(t *testing.T) {
+	var out bytes.Buffer
+	err := writeMessage(&out, &notification{JSONRPC: "2.0", Method: "exit"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Content-Length: 47\r\n\r\n{\"jsonrpc\":\"2.0\",\"method\":\"exit\",\"params\":null}", out.String())
+}
+
+func TestReadMessage(t *testing.T) {
+	input := "Content-Length: 2\r\nContent-Type: application/vscode-jsonrpc; charset=utf-8\r\n\r\n{}" +
+		"Content-Length: 4\r\n\r\nnull"
+	reader := bufio.NewReader(strings.NewReader(input))
+
+	content, err := readMessage(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(content))
+	content, err = readMessage(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(content))
+}
+
+func TestReadMessage_WithWrongHeaders(t *testing.T) {
+	tests := []struct {
+		name, input, expected string
+	}{
+		{
+			name:     "Missing Content-Length",
+			input:    "Content-Type: application/json\r\n\r\n{}",
+			expected: "missing Content-Length header",
+		},
+		{
+			name:     "Content-Length is not a number",
+			input:    "Content-Length: two\r\n\r\n{}",
+			expected: "invalid header 'Content-Length: two'",
+		},
+		{
+			name:     "Header without colon",
+			input:    "Content-Length 2\r\n\r\n{}",
+			expected: "invalid header 'Content-Length 2'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := readMessage(bufio.NewReader(strings.NewReader(test.input)))
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestURIToPath(t *testing.T) {
+	path, err := uriToPath("file:///home/user/my%20project")
+	assert.NoError(t, err)
+	assert.Equal(t, "/home/user/my project", path)
+
+	_, err = uriToPath("https://example.com/project")
+	assert.EqualError(t, err, "URI 'https://example.com/project' is not a file URI")
+}
+
+func TestPathToURI(t *testing.T) {
+	assert.Equal(t, "file:///home/user/my%20project/bar.go", pathToURI("/home/user/my project/bar.go"))
+}
@@ -0,0 +1,265 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+// Package lsp implements Language Server Protocol server, which publishes
+// diagnostics of imports taking part in cycles to editors.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/anticycle/anticycle/internal/pkg/scan"
+	"github.com/anticycle/anticycle/pkg/anticycle"
+	"github.com/anticycle/anticycle/pkg/serialize"
+)
+
+// Server publishes diagnostics on imports which take part in cycles,
+// and on imports which violate visibility of internal packages if enabled.
+// Workspace is scanned after initialization and again on every saved file,
+// only changed directories are parsed again.
+type Server struct {
+	// Version is reported to the client as version of the server.
+	Version string
+
+	excluded   []string
+	kinds      []string
+	visibility bool
+
+	tree        *scan.Tree
+	out         io.Writer
+	published   map[string]bool
+	initialized bool
+	shutdown    bool
+}
+
+// NewServer creates server which skips excluded directories and analyzes
+// only imports of given kinds, all kinds if the list is empty.
+func NewServer(excluded, kinds []string, visibility bool) *Server {
+	return &Server{
+		excluded:   excluded,
+		kinds:      kinds,
+		visibility: visibility,
+		published:  make(map[string]bool),
+	}
+}
+
+// Serve reads requests from in and writes responses and notifications to out,
+// until exit notification or end of input. Error is returned if the client
+// exits without shutdown request, like required by the protocol.
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	s.out = out
+	reader := bufio.NewReader(in)
+	for {
+		content, err := readMessage(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		req := &request{}
+		if err := json.Unmarshal(content, req); err != nil {
+			if err := s.replyError(nil, codeParseError, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return fmt.Errorf("exit without shutdown request")
+			}
+			return nil
+		}
+		if err := s.handle(req); err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches the request, only errors of writing to the client are returned.
+func (s *Server) handle(req *request) error {
+	if !s.initialized && req.Method != "initialize" {
+		if req.ID != nil {
+			return s.replyError(req.ID, codeServerNotInitialized, "server is not initialized")
+		}
+		return nil
+	}
+
+	switch req.Method {
+	case "initialize":
+		params := &initializeParams{}
+		if err := json.Unmarshal(req.Params, params); err != nil {
+			return s.replyError(req.ID, codeParseError, err.Error())
+		}
+		root, err := rootDir(params)
+		if err != nil {
+			return s.replyError(req.ID, codeParseError, err.Error())
+		}
+		s.tree = scan.NewTree(root, s.excluded)
+		s.initialized = true
+		return s.reply(req.ID, &initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync: textDocumentSyncOptions{OpenClose: true},
+			},
+			ServerInfo: serverInfo{Name: "anticycle", Version: s.Version},
+		})
+	case "initialized", "textDocument/didSave":
+		return s.publish()
+	case "shutdown":
+		s.shutdown = true
+		return s.reply(req.ID, nil)
+	}
+
+	if req.ID != nil {
+		return s.replyError(req.ID, codeMethodNotFound, fmt.Sprintf("method '%v' is not supported", req.Method))
+	}
+	return nil
+}
+
+// rootDir returns directory of the workspace, or the current directory if client has no workspace.
+func rootDir(params *initializeParams) (string, error) {
+	if params.RootURI != "" {
+		return uriToPath(params.RootURI)
+	}
+	if params.RootPath != "" {
+		return params.RootPath, nil
+	}
+	return os.Getwd()
+}
+
+// publish analyzes changed directories and publishes diagnostics of all files.
+// Files which had diagnostics before are published with empty list to clear them.
+// Errors of the analysis, e.g. syntax errors, are logged and diagnostics are kept.
+func (s *Server) publish() error {
+	diagnostics, err := s.diagnose()
+	if err != nil {
+		return s.notify("window/logMessage", &logMessageParams{Type: messageError, Message: err.Error()})
+	}
+	if diagnostics == nil {
+		return nil
+	}
+
+	uris := make([]string, 0, len(diagnostics)+len(s.published))
+	for uri := range diagnostics {
+		uris = append(uris, uri)
+	}
+	for uri := range s.published {
+		if _, ok := diagnostics[uri]; !ok {
+			uris = append(uris, uri)
+		}
+	}
+	sort.Strings(uris)
+
+	s.published = make(map[string]bool, len(diagnostics))
+	for _, uri := range uris {
+		fileDiagnostics, ok := diagnostics[uri]
+		if ok {
+			s.published[uri] = true
+		} else {
+			fileDiagnostics = make([]*diagnostic, 0)
+		}
+		err := s.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{URI: uri, Diagnostics: fileDiagnostics})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diagnose returns diagnostics grouped by URI of a file, or nil if no file changed since the previous call.
+func (s *Server) diagnose() (map[string][]*diagnostic, error) {
+	changed, err := s.tree.Refresh()
+	if err != nil || !changed {
+		return nil, err
+	}
+	packages := s.tree.Packages()
+	if len(s.kinds) > 0 {
+		packages, err = anticycle.FilterKinds(packages, s.kinds)
+		if err != nil {
+			return nil, err
+		}
+	}
+	anticycle.Suppress(packages)
+	packages, err = anticycle.FindCycles(packages)
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics := make(map[string][]*diagnostic)
+	add := func(file string, line, severity int, message string) {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			abs = file
+		}
+		uri := pathToURI(abs)
+		diagnostics[uri] = append(diagnostics[uri], &diagnostic{
+			Range:    lineRange(abs, line),
+			Severity: severity,
+			Source:   "anticycle",
+			Message:  message,
+		})
+	}
+	if s.visibility {
+		for _, v := range anticycle.Visibility(packages).Violations {
+			add(v.File, v.Import.Line, severityWarning,
+				fmt.Sprintf("import %q violates visibility of internal package", v.Import.Name))
+		}
+	}
+	// Visibility needs all packages, so packages are filtered after it.
+	analysis := anticycle.Analyze(anticycle.OnlyAffected(packages))
+	for _, finding := range serialize.CycleFindings(analysis) {
+		add(finding.File, finding.Line, severityError, finding.Message)
+	}
+	return diagnostics, nil
+}
+
+// lineRange returns range of the line without leading and trailing white spaces.
+// Lines are numbered from 1, like in the model, and characters are counted in UTF-16
+// code units, like required by the protocol. Unknown line is the first line of the file.
+func lineRange(path string, line int) textRange {
+	if line < 1 {
+		line = 1
+	}
+	r := textRange{Start: position{Line: line - 1}, End: position{Line: line - 1}}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return r
+	}
+	lines := strings.Split(string(content), "\n")
+	if line > len(lines) {
+		return r
+	}
+	text := strings.TrimRight(lines[line-1], " \t\r")
+	trimmed := strings.TrimLeft(text, " \t")
+	r.Start.Character = len(utf16.Encode([]rune(text[:len(text)-len(trimmed)])))
+	r.End.Character = len(utf16.Encode([]rune(text)))
+	return r
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}) error {
+	content, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	raw := json.RawMessage(content)
+	return writeMessage(s.out, &response{JSONRPC: "2.0", ID: id, Result: &raw})
+}
+
+func (s *Server) replyError(id *json.RawMessage, code int, message string) error {
+	return writeMessage(s.out, &response{JSONRPC: "2.0", ID: id, Error: &responseError{Code: code, Message: message}})
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	return writeMessage(s.out, &notification{JSONRPC: "2.0", Method: method, Params: params})
+}
@@ -0,0 +1,176 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anticycle/anticycle/internal/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+// client is a scripted language client connected to the server with pipes.
+type client struct {
+	t      *testing.T
+	writer *io.PipeWriter
+	reader *bufio.Reader
+	done   chan error
+}
+
+func startServer(t *testing.T, server *Server) *client {
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	c := &client{t: t, writer: inWriter, reader: bufio.NewReader(outReader), done: make(chan error, 1)}
+	go func() {
+		err := server.Serve(inReader, outWriter)
+		outWriter.Close()
+		c.done <- err
+	}()
+	return c
+}
+
+// send writes request with given id, or notification if id is zero.
+func (c *client) send(id int, method string, params interface{}) {
+	message := map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+	if id != 0 {
+		message["id"] = id
+	}
+	assert.NoError(c.t, writeMessage(c.writer, message))
+}
+
+func (c *client) receive() map[string]interface{} {
+	content, err := readMessage(c.reader)
+	assert.NoError(c.t, err)
+	message := make(map[string]interface{})
+	assert.NoError(c.t, json.Unmarshal(content, &message))
+	return message
+}
+
+// diagnostics receives publishDiagnostics notification and returns URI
+// and messages with lines of diagnostics.
+func (c *client) diagnostics() (string, []string) {
+	message := c.receive()
+	assert.Equal(c.t, "textDocument/publishDiagnostics", message["method"])
+	params := message["params"].(map[string]interface{})
+	lines := make([]string, 0)
+	for _, d := range params["diagnostics"].([]interface{}) {
+		diagnostic := d.(map[string]interface{})
+		start := diagnostic["range"].(map[string]interface{})["start"].(map[string]interface{})
+		end := diagnostic["range"].(map[string]interface{})["end"].(map[string]interface{})
+		lines = append(lines, fmt.Sprintf("%v:%v-%v %v %v", start["line"], start["character"], end["character"],
+			diagnostic["severity"], diagnostic["message"]))
+	}
+	return params["uri"].(string), lines
+}
+
+func (c *client) exit() error {
+	c.send(99, "shutdown", nil)
+	assert.Equal(c.t, map[string]interface{}{"jsonrpc": "2.0", "id": float64(99), "result": nil}, c.receive())
+	c.send(0, "exit", nil)
+	return <-c.done
+}
+
+func makeWorkspace(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "anticycle-lsp")
+	assert.NoError(t, err)
+	assert.NoError(t, fixtures.WriteSource(dir, "foo", "package foo"))
+	assert.NoError(t, fixtures.WriteSource(dir, "bar", fmt.Sprintf("package bar\n\nimport (\n\t\"fmt\"\n\t\"%s/baz\"\n)", dir)))
+	assert.NoError(t, fixtures.WriteSource(dir, "baz", fmt.Sprintf("package baz\n\nimport \"%s/bar\"", dir)))
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestServer(t *testing.T) {
+	dir, remove := makeWorkspace(t)
+	defer remove()
+
+	server := NewServer([]string{}, []string{}, false)
+	server.Version = "1.0.0"
+	c := startServer(t, server)
+
+	c.send(1, "initialize", map[string]interface{}{"rootUri": pathToURI(dir)})
+	result := c.receive()["result"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"name": "anticycle", "version": "1.0.0"}, result["serverInfo"])
+
+	c.send(0, "initialized", map[string]interface{}{})
+	uri, lines := c.diagnostics()
+	assert.Equal(t, pathToURI(filepath.Join(dir, "bar", "bar.go")), uri)
+	assert.Equal(t, []string{fmt.Sprintf("4:1-%d 1 import \"%s/baz\" creates a cycle: bar -> baz -> bar", len(dir)+7, dir)}, lines)
+	uri, lines = c.diagnostics()
+	assert.Equal(t, pathToURI(filepath.Join(dir, "baz", "baz.go")), uri)
+	assert.Equal(t, []string{fmt.Sprintf("2:0-%d 1 import \"%s/bar\" creates a cycle: baz -> bar -> baz", len(dir)+13, dir)}, lines)
+
+	assert.NoError(t, fixtures.WriteSource(dir, "baz", "package baz"))
+	c.send(0, "textDocument/didSave", map[string]interface{}{"textDocument": map[string]string{"uri": uri}})
+	uri, lines = c.diagnostics()
+	assert.Equal(t, pathToURI(filepath.Join(dir, "bar", "bar.go")), uri)
+	assert.Empty(t, lines)
+	uri, lines = c.diagnostics()
+	assert.Equal(t, pathToURI(filepath.Join(dir, "baz", "baz.go")), uri)
+	assert.Empty(t, lines)
+
+	assert.NoError(t, c.exit())
+}
+
+func TestServer_WhenSyntaxError(t *testing.T) {
+	dir, remove := makeWorkspace(t)
+	defer remove()
+	assert.NoError(t, fixtures.WriteSource(dir, "baz", "package baz\nimport ("))
+
+	c := startServer(t, NewServer([]string{}, []string{}, false))
+	c.send(1, "initialize", map[string]interface{}{"rootPath": dir})
+	c.receive()
+	c.send(0, "initialized", map[string]interface{}{})
+	message := c.receive()
+	assert.Equal(t, "window/logMessage", message["method"])
+	assert.Contains(t, message["params"].(map[string]interface{})["message"], "expected ')'")
+
+	assert.NoError(t, c.exit())
+}
+
+func TestServer_WithVisibility(t *testing.T) {
+	dir, remove := makeWorkspace(t)
+	defer remove()
+	assert.NoError(t, fixtures.WriteSource(dir, filepath.Join("foo", "internal", "qux"), "package qux"))
+	assert.NoError(t, fixtures.WriteSource(dir, "baz", fmt.Sprintf("package baz\n\nimport \"%s/foo/internal/qux\"", dir)))
+
+	c := startServer(t, NewServer([]string{}, []string{}, true))
+	c.send(1, "initialize", map[string]interface{}{"rootUri": pathToURI(dir)})
+	c.receive()
+	c.send(0, "initialized", map[string]interface{}{})
+	uri, lines := c.diagnostics()
+	assert.Equal(t, pathToURI(filepath.Join(dir, "baz", "baz.go")), uri)
+	assert.Equal(t, []string{fmt.Sprintf("2:0-%d 2 import \"%s/foo/internal/qux\" violates visibility of internal package",
+		len(dir)+26, dir)}, lines)
+
+	assert.NoError(t, c.exit())
+}
+
+func TestServer_WithWrongRequests(t *testing.T) {
+	c := startServer(t, NewServer([]string{}, []string{}, false))
+
+	c.send(1, "textDocument/hover", map[string]interface{}{})
+	assert.Equal(t, float64(codeServerNotInitialized), c.receive()["error"].(map[string]interface{})["code"])
+
+	c.send(2, "initialize", map[string]interface{}{"rootUri": "https://example.com"})
+	assert.Equal(t, "URI 'https://example.com' is not a file URI", c.receive()["error"].(map[string]interface{})["message"])
+
+	c.send(3, "initialize", map[string]interface{}{"rootPath": os.TempDir()})
+	c.receive()
+	c.send(4, "textDocument/hover", map[string]interface{}{})
+	assert.Equal(t, map[string]interface{}{
+		"code":    float64(codeMethodNotFound),
+		"message": "method 'textDocument/hover' is not supported",
+	}, c.receive()["error"])
+
+	c.send(0, "exit", nil)
+	assert.EqualError(t, <-c.done, "exit without shutdown request")
+}
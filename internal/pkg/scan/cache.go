@@ -0,0 +1,131 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// cacheFormat is a version of cache file. Caches of other versions are ignored.
+// It must be incremented whenever parsedFile or model.ImportInfo change.
+const cacheFormat = 2
+
+// Cache holds parsed files of a directory tree, so unchanged files are not parsed again.
+// Files are identified by path, size, modification time and hash of the content.
+// A nil Cache is valid and disables caching.
+type Cache struct {
+	path    string
+	files   map[string]*cachedFile
+	seen    map[string]bool
+	changed bool
+}
+
+type cacheData struct {
+	Format int                    `json:"format"`
+	Files  map[string]*cachedFile `json:"files"`
+}
+
+type cachedFile struct {
+	Size    int64       `json:"size"`
+	ModTime int64       `json:"modTime"`
+	Hash    string      `json:"hash"`
+	File    *parsedFile `json:"file"`
+}
+
+// OpenCache reads cache of the root directory from cache directory.
+// Missing, corrupted or outdated cache is read as empty.
+func OpenCache(cacheDir, root string) (*Cache, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	key := sha256.Sum256([]byte(absRoot))
+	cache := &Cache{
+		path:  filepath.Join(cacheDir, hex.EncodeToString(key[:])+".gob"),
+		files: make(map[string]*cachedFile),
+		seen:  make(map[string]bool),
+	}
+
+	content, err := ioutil.ReadFile(cache.path)
+	if err != nil {
+		return cache, nil
+	}
+	data := &cacheData{}
+	if err := gob.NewDecoder(bytes.NewReader(content)).Decode(data); err == nil && data.Format == cacheFormat && data.Files != nil {
+		cache.files = data.Files
+	}
+	return cache, nil
+}
+
+// get returns parsed file if the cache holds the same version of the file.
+func (c *Cache) get(path string, info os.FileInfo, hash string) *parsedFile {
+	if c == nil {
+		return nil
+	}
+	c.seen[path] = true
+	cached, ok := c.files[path]
+	if !ok || cached.Size != info.Size() || cached.ModTime != info.ModTime().UnixNano() || cached.Hash != hash {
+		return nil
+	}
+	return cached.File
+}
+
+func (c *Cache) put(path string, info os.FileInfo, hash string, file *parsedFile) {
+	if c == nil {
+		return
+	}
+	c.files[path] = &cachedFile{
+		Size:    info.Size(),
+		ModTime: info.ModTime().UnixNano(),
+		Hash:    hash,
+		File:    file,
+	}
+	c.changed = true
+}
+
+// Save writes the cache if any file was parsed or removed since it was read.
+// Files which were not seen are removed from the cache.
+func (c *Cache) Save() error {
+	if c == nil {
+		return nil
+	}
+	for path := range c.files {
+		if !c.seen[path] {
+			delete(c.files, path)
+			c.changed = true
+		}
+	}
+	if !c.changed {
+		return nil
+	}
+
+	var content bytes.Buffer
+	if err := gob.NewEncoder(&content).Encode(&cacheData{Format: cacheFormat, Files: c.files}); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return err
+	}
+	// Write to a temporary file first, so concurrent runs never read partial cache.
+	tmp, err := ioutil.TempFile(filepath.Dir(c.path), "cache")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(content.Bytes())
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
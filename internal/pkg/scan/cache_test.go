@@ -0,0 +1,152 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchPackages_WithCache(t *testing.T) {
+	dir, remove := makeProjectOneToOne("fetchCache")
+	defer remove()
+	cacheDir, removeCache := tmpDir("fetchCacheDir")
+	defer removeCache()
+
+	cold, err := FetchPackages(dir, []string{}, cacheDir)
+	assert.NoError(t, err)
+	cacheFiles, err := filepath.Glob(filepath.Join(cacheDir, "*.gob"))
+	assert.NoError(t, err)
+	assert.Len(t, cacheFiles, 1)
+
+	warm, err := FetchPackages(dir, []string{}, cacheDir)
+	assert.NoError(t, err)
+	assert.Equal(t, cold, warm)
+
+	uncached, err := FetchPackages(dir, []string{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, uncached, warm)
+}
+
+func TestCache_WithChangedFile(t *testing.T) {
+	dir, remove := makeProjectOneToOne("cacheChanged")
+	defer remove()
+	cacheDir, removeCache := tmpDir("cacheChangedDir")
+	defer removeCache()
+
+	_, err := FetchPackages(dir, []string{}, cacheDir)
+	assert.NoError(t, err)
+
+	// Content of the same size and modification time must be parsed again.
+	path := filepath.Join(dir, "bar", "bar.go")
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(path, []byte("package rab\nimport \"/tmp/anticycle/cacheChanged/baz\""), 0600)
+	assert.NoError(t, err)
+	err = os.Chtimes(path, time.Now(), info.ModTime())
+	assert.NoError(t, err)
+
+	cache, err := OpenCache(cacheDir, dir)
+	assert.NoError(t, err)
+	info, err = os.Stat(path)
+	assert.NoError(t, err)
+	file, err := parseFile(path, info, cache)
+	assert.NoError(t, err)
+	assert.Equal(t, "rab", file.Package)
+	assert.True(t, cache.changed)
+}
+
+func TestCache_WithRemovedFile(t *testing.T) {
+	dir, remove := makeProjectOneToOne("cacheRemoved")
+	defer remove()
+	cacheDir, removeCache := tmpDir("cacheRemovedDir")
+	defer removeCache()
+
+	_, err := FetchPackages(dir, []string{}, cacheDir)
+	assert.NoError(t, err)
+	err = os.RemoveAll(filepath.Join(dir, "foo"))
+	assert.NoError(t, err)
+	_, err = FetchPackages(dir, []string{}, cacheDir)
+	assert.NoError(t, err)
+
+	cache, err := OpenCache(cacheDir, dir)
+	assert.NoError(t, err)
+	assert.Len(t, cache.files, 2)
+	assert.NotContains(t, cache.files, filepath.Join(dir, "foo", "foo.go"))
+}
+
+func TestOpenCache_WithCorruptedCache(t *testing.T) {
+	dir, remove := makeProjectOneToOne("cacheCorrupted")
+	defer remove()
+	cacheDir, removeCache := tmpDir("cacheCorruptedDir")
+	defer removeCache()
+
+	cache, err := OpenCache(cacheDir, dir)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(cache.path, []byte("corrupted"), 0600)
+	assert.NoError(t, err)
+
+	cache, err = OpenCache(cacheDir, dir)
+	assert.NoError(t, err)
+	assert.Empty(t, cache.files)
+
+	packages, err := FetchPackages(dir, []string{}, cacheDir)
+	assert.NoError(t, err)
+	assert.Len(t, packages, 3)
+}
+
+func BenchmarkFetchPackages_ColdCache(b *testing.B) {
+	dir, remove := makeProjectLarge("benchFetchCold", 100, 10)
+	defer remove()
+	cacheDir, removeCache := tmpDir("benchFetchColdDir")
+	defer removeCache()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		os.RemoveAll(cacheDir)
+		b.StartTimer()
+		if _, err := FetchPackages(dir, []string{}, cacheDir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFetchPackages_WarmCache(b *testing.B) {
+	dir, remove := makeProjectLarge("benchFetchWarm", 100, 10)
+	defer remove()
+	cacheDir, removeCache := tmpDir("benchFetchWarmDir")
+	defer removeCache()
+	if _, err := FetchPackages(dir, []string{}, cacheDir); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := FetchPackages(dir, []string{}, cacheDir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFetchPackages_WithoutCache(b *testing.B) {
+	dir, remove := makeProjectLarge("benchFetchNoCache", 100, 10)
+	defer remove()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := FetchPackages(dir, []string{}, ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
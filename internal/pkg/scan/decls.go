@@ -0,0 +1,119 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// FileDecls holds package level identifiers declared in a file and identifiers
+// the file uses. The file is parsed without type checking, so identifiers
+// are known only by name.
+type FileDecls struct {
+	Path string
+	// Declared holds names of functions, types, variables and constants.
+	// Methods are not listed, they use the file which declares the receiver type.
+	Declared []string
+	// Referenced holds names which are not declared in the file,
+	// so they are declared in other files of the package or are predeclared.
+	Referenced []string
+	// Selected holds names selected from imported packages, like Println
+	// from fmt.Println, by import path. The package name is assumed to be
+	// the last element of the import path, unless the import has an alias.
+	Selected map[string][]string
+}
+
+// ParseDecls parses the whole file, unlike Fetch which parses only imports,
+// and finds declared and used identifiers.
+func ParseDecls(filePath string) (*FileDecls, error) {
+	fset := token.NewFileSet()
+	astFile, err := parser.ParseFile(fset, filePath, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	decls := &FileDecls{
+		Path:       filePath,
+		Declared:   make([]string, 0),
+		Referenced: make([]string, 0),
+		Selected:   make(map[string][]string),
+	}
+
+	for _, decl := range astFile.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				decls.Declared = appendName(decls.Declared, decl.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					decls.Declared = appendName(decls.Declared, spec.Name.Name)
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						decls.Declared = appendName(decls.Declared, name.Name)
+					}
+				}
+			}
+		}
+	}
+
+	imports := make(map[string]string, len(astFile.Imports))
+	for _, importSpec := range astFile.Imports {
+		importPath, err := strconv.Unquote(importSpec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(importPath)
+		if importSpec.Name != nil {
+			name = importSpec.Name.Name
+		}
+		if name != "_" && name != "." {
+			imports[name] = importPath
+		}
+	}
+
+	// Parser resolves identifiers declared in the file, including local
+	// variables, others are left unresolved.
+	for _, ident := range astFile.Unresolved {
+		if _, ok := imports[ident.Name]; !ok {
+			decls.Referenced = appendName(decls.Referenced, ident.Name)
+		}
+	}
+	ast.Inspect(astFile, func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok && ident.Obj == nil {
+			if importPath, ok := imports[ident.Name]; ok {
+				decls.Selected[importPath] = appendName(decls.Selected[importPath], selector.Sel.Name)
+			}
+		}
+		return true
+	})
+
+	sort.Strings(decls.Declared)
+	sort.Strings(decls.Referenced)
+	for _, names := range decls.Selected {
+		sort.Strings(names)
+	}
+	return decls, nil
+}
+
+// appendName appends the name unless it is already in the list or can not be referenced.
+func appendName(names []string, name string) []string {
+	if name == "_" || name == "init" || model.SliceContains(names, name) {
+		return names
+	}
+	return append(names, name)
+}
@@ -0,0 +1,73 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const declsSource = `package models
+
+import (
+	"fmt"
+	str "strings"
+	_ "image/png"
+
+	"app/api"
+)
+
+const Version = "1"
+
+var _, cache = 0, map[string]User{}
+
+type Handler struct{}
+
+func init() {}
+
+func (h *Handler) Serve(user User) string {
+	title := str.Title(user.Name)
+	fmt.Println(title, Version)
+	return api.Format(Render(title))
+}
+
+func Handle() *Handler {
+	return &Handler{}
+}
+`
+
+func TestParseDecls(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anticycle-decls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "handler.go")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(declsSource), 0600))
+
+	decls, err := ParseDecls(path)
+	assert.NoError(t, err)
+	assert.Equal(t, path, decls.Path)
+	assert.Equal(t, []string{"Handle", "Handler", "Version", "cache"}, decls.Declared)
+	assert.Equal(t, []string{"Render", "User", "string"}, decls.Referenced)
+	assert.Equal(t, map[string][]string{
+		"fmt":     {"Println"},
+		"strings": {"Title"},
+		"app/api": {"Format"},
+	}, decls.Selected)
+}
+
+func TestParseDecls_WithSyntaxError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anticycle-decls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "broken.go")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("package models\nfunc ("), 0600))
+
+	_, err = ParseDecls(path)
+	assert.Error(t, err)
+}
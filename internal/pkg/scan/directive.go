@@ -0,0 +1,75 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+const directivePrefix = "//anticycle:"
+
+// parseDirective splits comment like "//anticycle:ignore reason"
+// into the directive and its arguments.
+func parseDirective(comment string) (directive, args string, ok bool) {
+	if !strings.HasPrefix(comment, directivePrefix) {
+		return "", "", false
+	}
+	text := strings.TrimPrefix(comment, directivePrefix)
+	fields := strings.SplitN(text, " ", 2)
+	if len(fields) > 1 {
+		args = strings.TrimSpace(fields[1])
+	}
+	return fields[0], args, true
+}
+
+// ignoreDirective looks for //anticycle:ignore in comments attached to the import,
+// either in the same line or in the line above.
+func ignoreDirective(importSpec *ast.ImportSpec) (reason string, ok bool) {
+	for _, group := range []*ast.CommentGroup{importSpec.Comment, importSpec.Doc} {
+		if group == nil {
+			continue
+		}
+		for _, comment := range group.List {
+			if directive, args, found := parseDirective(comment.Text); found && directive == model.DirectiveIgnore {
+				return args, true
+			}
+		}
+	}
+	return "", false
+}
+
+// allowCycleDirectives finds package level //anticycle:allow-cycle with=x/y reason
+// directives in all comments of the file which are not attached to imports.
+func allowCycleDirectives(fset *token.FileSet, pkg, path string, astFile *ast.File) []*model.Suppression {
+	suppressions := make([]*model.Suppression, 0)
+	for _, group := range astFile.Comments {
+		for _, comment := range group.List {
+			directive, args, found := parseDirective(comment.Text)
+			if !found || directive != model.DirectiveAllowCycle {
+				continue
+			}
+			fields := strings.SplitN(args, " ", 2)
+			if !strings.HasPrefix(fields[0], "with=") {
+				continue
+			}
+			suppression := &model.Suppression{
+				Pkg:       pkg,
+				File:      path,
+				Line:      fset.Position(comment.Pos()).Line,
+				Directive: model.DirectiveAllowCycle,
+				Import:    strings.Trim(strings.TrimPrefix(fields[0], "with="), `"`),
+			}
+			if len(fields) > 1 {
+				suppression.Reason = strings.TrimSpace(fields[1])
+			}
+			suppressions = append(suppressions, suppression)
+		}
+	}
+	return suppressions
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const directiveSource = `// Package foo is a test package.
+//anticycle:allow-cycle with=pkg/bar plugins register themselves
+//anticycle:allow-cycle without argument
+package foo
+
+//anticycle:allow-cycle with="pkg/baz"
+
+import (
+	"pkg/bar" //anticycle:ignore registry is intentional
+	//anticycle:ignore
+	"pkg/baz"
+	"pkg/qux" // anticycle:ignore is not a directive
+)
+`
+
+func TestParseDirective(t *testing.T) {
+	directive, args, ok := parseDirective("//anticycle:ignore  the reason ")
+	assert.True(t, ok)
+	assert.Equal(t, "ignore", directive)
+	assert.Equal(t, "the reason", args)
+
+	_, _, ok = parseDirective("// anticycle:ignore")
+	assert.False(t, ok)
+}
+
+func TestSuppressionDirectives(t *testing.T) {
+	fset := token.NewFileSet()
+	astFile, err := parser.ParseFile(fset, "foo/foo.go", directiveSource, parser.ImportsOnly|parser.ParseComments)
+	assert.NoError(t, err)
+
+	reasons := make([]string, 0)
+	for _, importSpec := range astFile.Imports {
+		if reason, ok := ignoreDirective(importSpec); ok {
+			reasons = append(reasons, importSpec.Path.Value+" "+reason)
+		}
+	}
+	assert.Equal(t, []string{`"pkg/bar" registry is intentional`, `"pkg/baz" `}, reasons)
+
+	expected := []*model.Suppression{
+		{
+			Pkg:       "foo",
+			File:      "foo/foo.go",
+			Line:      2,
+			Directive: model.DirectiveAllowCycle,
+			Import:    "pkg/bar",
+			Reason:    "plugins register themselves",
+		},
+		{
+			Pkg:       "foo",
+			File:      "foo/foo.go",
+			Line:      6,
+			Directive: model.DirectiveAllowCycle,
+			Import:    "pkg/baz",
+		},
+	}
+	assert.Equal(t, expected, allowCycleDirectives(fset, "foo", "foo/foo.go", astFile))
+}
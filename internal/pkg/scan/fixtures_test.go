@@ -8,6 +8,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/anticycle/anticycle/internal/pkg/fixtures"
 )
 
 func tmpDir(rootDir string) (string, func()) {
@@ -33,27 +36,6 @@ func tmpDir(rootDir string) (string, func()) {
 	return dir, remove
 }
 
-func tmpFile(dir, filename, data string) (*os.File, error) {
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		if err := os.Mkdir(dir, 0700); err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
-		}
-	}
-
-	f, err := os.Create(filepath.Join(dir, filename))
-	if err != nil {
-		return nil, err
-	}
-	if _, err := f.Write([]byte(data)); err != nil {
-		return nil, err
-	}
-	if err := f.Close(); err != nil {
-		return nil, err
-	}
-	return f, nil
-}
-
 func makeProjectNoCycles(testName string) (string, func()) {
 	dir, remove := tmpDir(testName)
 	packages := []struct{ Name, Data string }{
@@ -125,10 +107,30 @@ func makeProjectDiagonalSquare(testName string) (string, func()) {
 
 func _generateProject(dir string, packages []struct{ Name, Data string }) error {
 	for _, pkg := range packages {
-		_, err := tmpFile(filepath.Join(dir, pkg.Name), fmt.Sprintf("%v.go", pkg.Name), pkg.Data)
-		if err != nil {
+		if err := fixtures.WriteSource(dir, pkg.Name, pkg.Data); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// makeProjectLarge generates packages with many files, each file has a long
+// doc comment, imports a few standard packages and the next package.
+func makeProjectLarge(testName string, pkgCount, fileCount int) (string, func()) {
+	dir, remove := tmpDir(testName)
+	doc := strings.Repeat("// Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod.\n", 40)
+	for p := 0; p < pkgCount; p++ {
+		name := fmt.Sprintf("pkg%d", p)
+		for f := 0; f < fileCount; f++ {
+			data := fmt.Sprintf("%vpackage %v\n\nimport (\n\t\"bytes\"\n\t\"errors\"\n\t\"fmt\"\n\t\"io\"\n\t\"os\"\n"+
+				"\t\"sort\"\n\t\"strings\"\n\t\"/tmp/anticycle/%v/pkg%d\"\n)\n\n"+
+				"func F%d() {\n\tfmt.Fprintln(os.Stdout, strings.Repeat(\"-\", %d))\n}\n", doc, name, testName, (p+1)%pkgCount, f, f)
+			if err := fixtures.WriteFile(filepath.Join(dir, name), fmt.Sprintf("file%d.go", f), data); err != nil {
+				remove()
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	}
+	return dir, remove
+}
@@ -0,0 +1,174 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Index allows to find package pointed by import.
+type Index struct {
+	packages     []*model.Pkg
+	byImportPath map[string]int
+	byName       map[string][]int
+}
+
+// NewIndex creates Index of given packages.
+func NewIndex(packages []*model.Pkg) *Index {
+	idx := &Index{
+		packages:     packages,
+		byImportPath: make(map[string]int, len(packages)),
+		byName:       make(map[string][]int, len(packages)),
+	}
+	for i, pkg := range packages {
+		// External test package shares import path of the tested package, but can not be imported.
+		if pkg.ImportPath != "" && !isExternalTest(pkg) {
+			idx.byImportPath[pkg.ImportPath] = i
+		}
+		idx.byName[pkg.Name] = append(idx.byName[pkg.Name], i)
+	}
+	return idx
+}
+
+// Find returns index of package pointed by import or -1 if there is no such package.
+func (idx *Index) Find(imp *model.ImportInfo) int {
+	if imp.Kind == model.KindPseudo {
+		return -1
+	}
+	if i, ok := idx.byImportPath[imp.Name]; ok {
+		return i
+	}
+	for _, i := range idx.byName[imp.NameShort] {
+		if refersTo(imp, idx.packages[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// refersTo checks if import points to the package. Import path resolved
+// from go.mod is exact, otherwise package directory must be a part of import name.
+func refersTo(imp *model.ImportInfo, pkg *model.Pkg) bool {
+	if imp.Kind == model.KindPseudo {
+		return false
+	}
+	if pkg.ImportPath != "" && imp.Name == pkg.ImportPath {
+		return !isExternalTest(pkg)
+	}
+	return imp.NameShort == pkg.Name && strings.Contains(imp.Name, pkg.Path)
+}
+
+// isExternalTest checks if package is an external test package, e.g. foo_test.
+func isExternalTest(pkg *model.Pkg) bool {
+	return strings.HasSuffix(pkg.Name, "_test")
+}
+
+// Dependencies takes list of packages and builds adjacency list of imports
+// between them. Element at index i holds sorted indexes of packages imported by packages[i].
+// Imports which do not point to any of the given packages are skipped.
+// Suppressed imports are kept, because packages still depend on each other.
+func Dependencies(packages []*model.Pkg) [][]int {
+	return dependencies(packages, true)
+}
+
+// CycleDependencies works like Dependencies, but skips suppressed imports,
+// so imports acknowledged by directives take no part in cycles.
+func CycleDependencies(packages []*model.Pkg) [][]int {
+	return dependencies(packages, false)
+}
+
+func dependencies(packages []*model.Pkg, suppressed bool) [][]int {
+	idx := NewIndex(packages)
+	deps := make([][]int, len(packages))
+	for i, pkg := range packages {
+		deps[i] = make([]int, 0, len(pkg.Imports))
+		seen := make(map[int]bool, len(pkg.Imports))
+		for _, imp := range pkg.Imports {
+			if imp.Suppressed && !suppressed {
+				continue
+			}
+			impIdx := idx.Find(imp)
+			if impIdx >= 0 && !seen[impIdx] {
+				seen[impIdx] = true
+				deps[i] = append(deps[i], impIdx)
+			}
+		}
+		// Imports are stored in hash map, so sort to keep output deterministic.
+		sort.Ints(deps[i])
+	}
+	return deps
+}
+
+// StronglyConnected takes adjacency list and using Tarjan's algorithm finds
+// strongly connected components. Each component holds sorted node indexes.
+// Components are returned in reverse topological order, it is every component
+// comes after all components it depends on.
+func StronglyConnected(deps [][]int) [][]int {
+	t := &tarjan{
+		deps:    deps,
+		index:   make([]int, len(deps)),
+		low:     make([]int, len(deps)),
+		onStack: make([]bool, len(deps)),
+		stack:   make([]int, 0, len(deps)),
+		result:  make([][]int, 0, len(deps)),
+	}
+	for i := range t.index {
+		t.index[i] = -1
+	}
+	for node := range deps {
+		if t.index[node] == -1 {
+			t.connect(node)
+		}
+	}
+	return t.result
+}
+
+type tarjan struct {
+	deps    [][]int
+	counter int
+	index   []int
+	low     []int
+	onStack []bool
+	stack   []int
+	result  [][]int
+}
+
+func (t *tarjan) connect(node int) {
+	t.index[node] = t.counter
+	t.low[node] = t.counter
+	t.counter++
+	t.stack = append(t.stack, node)
+	t.onStack[node] = true
+
+	for _, next := range t.deps[node] {
+		if t.index[next] == -1 {
+			t.connect(next)
+			if t.low[next] < t.low[node] {
+				t.low[node] = t.low[next]
+			}
+		} else if t.onStack[next] && t.index[next] < t.low[node] {
+			t.low[node] = t.index[next]
+		}
+	}
+
+	if t.low[node] != t.index[node] {
+		return
+	}
+	component := make([]int, 0, 1)
+	for {
+		last := t.stack[len(t.stack)-1]
+		t.stack = t.stack[:len(t.stack)-1]
+		t.onStack[last] = false
+		component = append(component, last)
+		if last == node {
+			break
+		}
+	}
+	sort.Ints(component)
+	t.result = append(t.result, component)
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencies(t *testing.T) {
+	dir, remove := makeProjectDiagonalSquare("dependencies")
+	defer remove()
+	packages, err := FetchPackages(dir, []string{}, "")
+	assert.NoError(t, err)
+
+	// packages are in alphabetical order: bar, baz, foo, pas
+	expected := [][]int{{2}, {0}, {3}, {1}}
+	assert.Equal(t, expected, Dependencies(packages))
+}
+
+func TestDependencies_SkipExternalPackages(t *testing.T) {
+	dir, remove := makeProjectNoCycles("dependenciesNoCycle")
+	defer remove()
+	packages, err := FetchPackages(dir, []string{}, "")
+	assert.NoError(t, err)
+
+	// bar imports only fmt, baz and foo import bar
+	expected := [][]int{{}, {0}, {0}}
+	assert.Equal(t, expected, Dependencies(packages))
+}
+
+func TestStronglyConnected(t *testing.T) {
+	tests := []struct {
+		name     string
+		deps     [][]int
+		expected [][]int
+	}{
+		{
+			name:     "no cycles",
+			deps:     [][]int{{1}, {2}, {}},
+			expected: [][]int{{2}, {1}, {0}},
+		},
+		{
+			name:     "one to one",
+			deps:     [][]int{{1}, {0, 2}, {}},
+			expected: [][]int{{2}, {0, 1}},
+		},
+		{
+			name:     "diagonal square",
+			deps:     [][]int{{2}, {0}, {3}, {1}},
+			expected: [][]int{{0, 1, 2, 3}},
+		},
+		{
+			name:     "empty graph",
+			deps:     [][]int{},
+			expected: [][]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, StronglyConnected(tt.deps))
+		})
+	}
+}
+
+func TestDependencies_ByImportPath(t *testing.T) {
+	foo := model.NewPkg()
+	foo.Name = "foo"
+	foo.Path = "/tmp/anticycle/importPath/foo"
+	foo.ImportPath = "example.com/m/foo"
+	foo.Imports["example.com/m/bar"] = &model.ImportInfo{Name: "example.com/m/bar", NameShort: "bar"}
+	foo.Imports["bar"] = &model.ImportInfo{Name: "bar", NameShort: "bar", Kind: model.KindStdlib}
+
+	bar := model.NewPkg()
+	bar.Name = "bar"
+	bar.Path = "/tmp/anticycle/importPath/bar"
+	bar.ImportPath = "example.com/m/bar"
+
+	assert.Equal(t, [][]int{{1}, {}}, Dependencies([]*model.Pkg{foo, bar}))
+}
+
+func TestDependencies_WithExternalTestPackage(t *testing.T) {
+	foo := model.NewPkg()
+	foo.Name = "foo"
+	foo.Path = "/tmp/anticycle/externalTest/foo"
+	foo.ImportPath = "example.com/m/foo"
+
+	fooTest := model.NewPkg()
+	fooTest.Name = "foo_test"
+	fooTest.Path = foo.Path
+	fooTest.ImportPath = foo.ImportPath
+	fooTest.Imports["example.com/m/foo"] = &model.ImportInfo{Name: "example.com/m/foo", NameShort: "foo"}
+
+	assert.Equal(t, [][]int{{}, {0}}, Dependencies([]*model.Pkg{foo, fooTest}))
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"go/build"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// stdlib checks if import belongs to standard library
+// by looking for package directory in GOROOT.
+type stdlib struct {
+	src   string
+	known map[string]bool
+}
+
+func newStdlib(goroot string) *stdlib {
+	src := ""
+	if info, err := os.Stat(filepath.Join(goroot, "src")); err == nil && info.IsDir() {
+		src = filepath.Join(goroot, "src")
+	}
+	return &stdlib{src: src, known: make(map[string]bool)}
+}
+
+func (s *stdlib) contains(name string) bool {
+	if found, ok := s.known[name]; ok {
+		return found
+	}
+	// Without GOROOT standard library is unknown, and imports are not guessed
+	// to be a part of it, because they could be GOPATH-style imports.
+	found := false
+	if s.src != "" {
+		info, err := os.Stat(filepath.Join(s.src, filepath.FromSlash(name)))
+		found = err == nil && info.IsDir()
+	}
+	s.known[name] = found
+	return found
+}
+
+// ClassifyImports sets kind of every import of given packages again,
+// e.g. after imports were modified. Modules are taken from packages.
+func ClassifyImports(packages []*model.Pkg) {
+	modules := make([]string, 0)
+	for _, pkg := range packages {
+		if pkg.Module != "" && !model.SliceContains(modules, pkg.Module) {
+			modules = append(modules, pkg.Module)
+		}
+	}
+	sort.Strings(modules)
+	classifyImports(packages, modules)
+}
+
+// classifyImports sets kind of every import of given packages.
+// Modules are paths of all modules found while scanning.
+func classifyImports(packages []*model.Pkg, modules []string) {
+	std := newStdlib(build.Default.GOROOT)
+	idx := NewIndex(packages)
+	for _, pkg := range packages {
+		for _, imp := range pkg.Imports {
+			imp.Kind = importKind(imp, pkg, modules, std, idx)
+		}
+		for _, file := range pkg.Files {
+			for _, imp := range file.Imports {
+				imp.Kind = importKind(imp, pkg, modules, std, idx)
+			}
+		}
+	}
+}
+
+func importKind(imp *model.ImportInfo, pkg *model.Pkg, modules []string, std *stdlib, idx *Index) string {
+	switch {
+	case imp.Name == "C" || imp.Name == "unsafe":
+		return model.KindPseudo
+	case pkg.Module != "" && hasPathPrefix(imp.Name, pkg.Module):
+		return model.KindModule
+	case idx.Find(imp) >= 0:
+		// Import which points to a scanned package is never taken for standard library.
+		return model.KindLocal
+	case std.contains(imp.Name):
+		return model.KindStdlib
+	}
+	for _, mod := range modules {
+		if hasPathPrefix(imp.Name, mod) {
+			return model.KindLocal
+		}
+	}
+	return model.KindExternal
+}
+
+// hasPathPrefix checks if path is equal to prefix or is a subdirectory of prefix.
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
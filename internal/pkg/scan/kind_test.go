@@ -0,0 +1,98 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"go/build"
+	"path"
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStdlibContains(t *testing.T) {
+	std := newStdlib(build.Default.GOROOT)
+	assert.True(t, std.contains("fmt"))
+	assert.True(t, std.contains("net/http"))
+	assert.False(t, std.contains("github.com/stretchr/testify"))
+	assert.False(t, std.contains("fmt/notexist"))
+}
+
+func TestStdlibContains_WithoutGoroot(t *testing.T) {
+	std := newStdlib("/tmp/anticycle/not/exist")
+	assert.False(t, std.contains("fmt"))
+	assert.False(t, std.contains("testdata/onetoone/baz"))
+	assert.False(t, std.contains("example.com/fmt"))
+}
+
+func TestClassifyImports(t *testing.T) {
+	imports := []string{
+		"C",
+		"unsafe",
+		"fmt",
+		"example.com/m/bar",
+		"example.com/other/x",
+		"github.com/external/fake/foo",
+		"/tmp/anticycle/classify/baz",
+	}
+	expected := []string{
+		model.KindPseudo,
+		model.KindPseudo,
+		model.KindStdlib,
+		model.KindModule,
+		model.KindLocal,
+		model.KindExternal,
+		model.KindLocal,
+	}
+
+	foo := model.NewPkg()
+	foo.Name = "foo"
+	foo.Path = "/tmp/anticycle/classify/foo"
+	foo.Module = "example.com/m"
+	file := model.NewFile()
+	for _, name := range imports {
+		imp := &model.ImportInfo{Name: name, NameShort: path.Base(name)}
+		file.Imports = append(file.Imports, imp)
+	}
+	foo.Files = append(foo.Files, file)
+
+	baz := model.NewPkg()
+	baz.Name = "baz"
+	baz.Path = "/tmp/anticycle/classify/baz"
+
+	classifyImports([]*model.Pkg{foo, baz}, []string{"example.com/m", "example.com/other"})
+
+	result := make([]string, 0, len(imports))
+	for _, imp := range file.Imports {
+		result = append(result, imp.Kind)
+	}
+	assert.Equal(t, expected, result)
+}
+
+func TestClassifyImports_WithoutGoroot(t *testing.T) {
+	goroot := build.Default.GOROOT
+	build.Default.GOROOT = "/tmp/anticycle/not/exist"
+	defer func() { build.Default.GOROOT = goroot }()
+
+	bar := model.NewPkg()
+	bar.Name = "bar"
+	bar.Path = "testdata/onetoone/bar"
+	file := model.NewFile()
+	file.Imports = []*model.ImportInfo{
+		{Name: "fmt", NameShort: "fmt"},
+		{Name: "testdata/onetoone/baz", NameShort: "baz", Kind: model.KindStdlib},
+	}
+	bar.Files = append(bar.Files, file)
+	baz := model.NewPkg()
+	baz.Name = "baz"
+	baz.Path = "testdata/onetoone/baz"
+	packages := []*model.Pkg{bar, baz}
+
+	classifyImports(packages, []string{})
+	assert.Equal(t, model.KindExternal, file.Imports[0].Kind)
+	assert.Equal(t, model.KindLocal, file.Imports[1].Kind)
+	assert.Equal(t, 1, NewIndex(packages).Find(file.Imports[1]))
+}
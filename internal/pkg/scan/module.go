@@ -0,0 +1,94 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// module is a Go module defined by go.mod file.
+type module struct {
+	Path string
+	Dir  string
+}
+
+// modules resolves import paths of directories based on the nearest go.mod file.
+// Results are cached per directory, so each go.mod is read only once.
+type modules struct {
+	dirs map[string]*module
+}
+
+func newModules() *modules {
+	return &modules{dirs: make(map[string]*module)}
+}
+
+// importPath returns import path of package in given directory and path
+// of its module or empty strings if directory does not belong to any module.
+func (m *modules) importPath(dir string) (string, string) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", ""
+	}
+	mod := m.lookup(abs)
+	if mod == nil {
+		return "", ""
+	}
+	rel, err := filepath.Rel(mod.Dir, abs)
+	if err != nil {
+		return "", mod.Path
+	}
+	return path.Join(mod.Path, filepath.ToSlash(rel)), mod.Path
+}
+
+// paths returns sorted paths of all modules found so far.
+func (m *modules) paths() []string {
+	paths := make([]string, 0)
+	for _, mod := range m.dirs {
+		if mod != nil && !model.SliceContains(paths, mod.Path) {
+			paths = append(paths, mod.Path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (m *modules) lookup(dir string) *module {
+	if mod, ok := m.dirs[dir]; ok {
+		return mod
+	}
+	var mod *module
+	if modPath := readModulePath(filepath.Join(dir, "go.mod")); modPath != "" {
+		mod = &module{Path: modPath, Dir: dir}
+	} else if parent := filepath.Dir(dir); parent != dir {
+		mod = m.lookup(parent)
+	}
+	m.dirs[dir] = mod
+	return mod
+}
+
+// readModulePath reads module path from module directive of go.mod file.
+// Returns empty string if file does not exist or has no module directive.
+func readModulePath(gomod string) string {
+	content, err := ioutil.ReadFile(gomod)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"`")
+		}
+	}
+	return ""
+}
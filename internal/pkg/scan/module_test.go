@@ -0,0 +1,52 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/anticycle/anticycle/internal/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModulesImportPath(t *testing.T) {
+	dir, remove := makeProjectNoCycles("modules")
+	defer remove()
+	err := fixtures.WriteFile(dir, "go.mod", "// comment\nmodule example.com/m // trailing\n\nrequire example.com/x v1.0.0\n")
+	assert.NoError(t, err)
+	err = fixtures.WriteFile(filepath.Join(dir, "bar"), "go.mod", "module \"example.com/bar\"\n")
+	assert.NoError(t, err)
+
+	mods := newModules()
+	importPath, module := mods.importPath(dir)
+	assert.Equal(t, "example.com/m", importPath)
+	assert.Equal(t, "example.com/m", module)
+
+	importPath, module = mods.importPath(filepath.Join(dir, "foo"))
+	assert.Equal(t, "example.com/m/foo", importPath)
+	assert.Equal(t, "example.com/m", module)
+
+	importPath, module = mods.importPath(filepath.Join(dir, "bar"))
+	assert.Equal(t, "example.com/bar", importPath)
+	assert.Equal(t, "example.com/bar", module)
+
+	assert.Equal(t, []string{"example.com/bar", "example.com/m"}, mods.paths())
+}
+
+func TestModulesImportPath_OutsideOfModule(t *testing.T) {
+	dir, remove := makeProjectNoCycles("noModules")
+	defer remove()
+
+	mods := newModules()
+	importPath, module := mods.importPath(filepath.Join(dir, "foo"))
+	assert.Equal(t, "", importPath)
+	assert.Equal(t, "", module)
+	assert.Empty(t, mods.paths())
+}
+
+func TestReadModulePath_FileNotExist(t *testing.T) {
+	assert.Equal(t, "", readModulePath("/tmp/anticycle/not/exist/go.mod"))
+}
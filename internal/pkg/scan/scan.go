@@ -5,30 +5,35 @@
 package scan
 
 import (
-	"strings"
-
 	"github.com/anticycle/anticycle/pkg/model"
 )
 
 // FetchPackages walks recursively given directory skipping excluded directories
-// and build list of packages.
-func FetchPackages(dir string, excluded []string) ([]*model.Pkg, error) {
-	packages, err := walkDir(dir, excluded)
+// and build list of packages. Parsed files are kept in cache directory,
+// so unchanged files are not parsed again. Cache is disabled if cacheDir is empty.
+func FetchPackages(dir string, excluded []string, cacheDir string) ([]*model.Pkg, error) {
+	var cache *Cache
+	if cacheDir != "" {
+		var err error
+		cache, err = OpenCache(cacheDir, dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	packages, err := walkDir(dir, excluded, cache)
 	if err != nil {
 		return nil, err
 	}
+	if err := cache.Save(); err != nil {
+		return nil, err
+	}
 	return packages, nil
 }
 
 // FindCycles takes list of packages and using Roy-Warshall algorithm
-// marks all cycles between packages.
+// marks all cycles between packages. Suppressed imports are skipped.
 func FindCycles(packages []*model.Pkg) ([]*model.Pkg, error) {
 	// 2D array where rows are start nodes and columns are end nodes
-	pkgToInt := make(map[string]int)
-	for idx, pkg := range packages {
-		pkgToInt[pkg.Name] = idx
-	}
-
 	// allocate composed 2d slice
 	nodes := len(packages)
 	graph := make([][]uint16, nodes, nodes)
@@ -37,12 +42,9 @@ func FindCycles(packages []*model.Pkg) ([]*model.Pkg, error) {
 	}
 
 	// fill graph with nodes
-	for idx, pkg := range packages {
-		for _, imp := range pkg.Imports {
-			impIdx, ok := pkgToInt[imp.NameShort]
-			if ok && strings.Contains(imp.Name, packages[impIdx].Path) {
-				graph[idx][impIdx] = 1
-			}
+	for idx, deps := range CycleDependencies(packages) {
+		for _, impIdx := range deps {
+			graph[idx][impIdx] = 1
 		}
 	}
 
@@ -68,7 +70,7 @@ func FindCycles(packages []*model.Pkg) ([]*model.Pkg, error) {
 					// check which file is affected
 					for _, file := range packages[i].Files {
 						for _, imp := range file.Imports {
-							if strings.HasSuffix(imp.Name, packages[j].Path) {
+							if !imp.Suppressed && refersTo(imp, packages[j]) {
 								cycle := &model.Cycle{
 									AffectedFile:   file.Path,
 									AffectedImport: imp,
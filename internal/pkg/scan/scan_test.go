@@ -24,6 +24,7 @@ func TestFetchPackages(t *testing.T) {
 					Name:      "fmt",
 					NameShort: "fmt",
 					Alias:     nil,
+					Kind:      model.KindStdlib,
 				},
 			},
 			Files: []*model.File{
@@ -34,6 +35,8 @@ func TestFetchPackages(t *testing.T) {
 							Name:      "fmt",
 							NameShort: "fmt",
 							Alias:     nil,
+							Kind:      model.KindStdlib,
+							Line:      2,
 						},
 					},
 				},
@@ -49,6 +52,7 @@ func TestFetchPackages(t *testing.T) {
 					Name:      "/tmp/anticycle/fetchNoCycle/bar",
 					NameShort: "bar",
 					Alias:     nil,
+					Kind:      model.KindLocal,
 				},
 			},
 			Files: []*model.File{
@@ -59,6 +63,8 @@ func TestFetchPackages(t *testing.T) {
 							Name:      "/tmp/anticycle/fetchNoCycle/bar",
 							NameShort: "bar",
 							Alias:     nil,
+							Kind:      model.KindLocal,
+							Line:      2,
 						},
 					},
 				},
@@ -74,6 +80,7 @@ func TestFetchPackages(t *testing.T) {
 					Name:      "/tmp/anticycle/fetchNoCycle/bar",
 					NameShort: "bar",
 					Alias:     nil,
+					Kind:      model.KindLocal,
 				},
 			},
 			Files: []*model.File{
@@ -84,6 +91,8 @@ func TestFetchPackages(t *testing.T) {
 							Name:      "/tmp/anticycle/fetchNoCycle/bar",
 							NameShort: "bar",
 							Alias:     nil,
+							Kind:      model.KindLocal,
+							Line:      2,
 						},
 					},
 				},
@@ -92,7 +101,7 @@ func TestFetchPackages(t *testing.T) {
 			HaveCycle: false,
 		},
 	}
-	packages, err := FetchPackages(dir, []string{})
+	packages, err := FetchPackages(dir, []string{}, "")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expected, packages)
 }
@@ -110,6 +119,7 @@ func TestFetchPackages_WithExcludedDir(t *testing.T) {
 					Name:      "fmt",
 					NameShort: "fmt",
 					Alias:     nil,
+					Kind:      model.KindStdlib,
 				},
 			},
 			Files: []*model.File{
@@ -120,6 +130,8 @@ func TestFetchPackages_WithExcludedDir(t *testing.T) {
 							Name:      "fmt",
 							NameShort: "fmt",
 							Alias:     nil,
+							Kind:      model.KindStdlib,
+							Line:      2,
 						},
 					},
 				},
@@ -128,7 +140,7 @@ func TestFetchPackages_WithExcludedDir(t *testing.T) {
 			HaveCycle: false,
 		},
 	}
-	packages, err := FetchPackages(dir, []string{"baz", "foo"})
+	packages, err := FetchPackages(dir, []string{"baz", "foo"}, "")
 	assert.NoError(t, err)
 	assert.EqualValues(t, expected, packages)
 }
@@ -368,7 +380,7 @@ func TestFindCycles_FalsePositiveExternalPkgNameOverlap(t *testing.T) {
 func BenchmarkFindCycles_NoCycles(b *testing.B) {
 	dir, remove := makeProjectNoCycles("benchFindNoCycle")
 	defer remove()
-	packages, err := FetchPackages(dir, []string{})
+	packages, err := FetchPackages(dir, []string{}, "")
 	assert.NoError(b, err)
 	assert.Len(b, packages, 3)
 
@@ -382,7 +394,7 @@ func BenchmarkFindCycles_NoCycles(b *testing.B) {
 func BenchmarkFindCycles_OneToOne(b *testing.B) {
 	dir, remove := makeProjectOneToOne("benchFindOneToOne")
 	defer remove()
-	packages, err := FetchPackages(dir, []string{})
+	packages, err := FetchPackages(dir, []string{}, "")
 	assert.NoError(b, err)
 	assert.Len(b, packages, 3)
 
@@ -396,7 +408,7 @@ func BenchmarkFindCycles_OneToOne(b *testing.B) {
 func BenchmarkFindCycles_Triangle(b *testing.B) {
 	dir, remove := makeProjectTriangle("benchFindTriangle")
 	defer remove()
-	packages, err := FetchPackages(dir, []string{})
+	packages, err := FetchPackages(dir, []string{}, "")
 	assert.NoError(b, err)
 	assert.Len(b, packages, 3)
 
@@ -410,7 +422,7 @@ func BenchmarkFindCycles_Triangle(b *testing.B) {
 func BenchmarkFindCycles_DiagonalSquare(b *testing.B) {
 	dir, remove := makeProjectDiagonalSquare("benchFindDiagonalSquare")
 	defer remove()
-	packages, err := FetchPackages(dir, []string{})
+	packages, err := FetchPackages(dir, []string{}, "")
 	assert.NoError(b, err)
 	assert.Len(b, packages, 4)
 
@@ -0,0 +1,137 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Tree keeps parsed files of a directory tree in memory. Refresh parses again
+// only directories where .go files or go.mod were added, removed or modified.
+// Parsed files may also be kept in cache, which is nil for trees kept in memory.
+type Tree struct {
+	dir      string
+	excluded []string
+	cache    *Cache
+	dirs     []*treeDir
+}
+
+// treeDir holds parsed files of a single directory and stamps of files
+// which were used to detect changes.
+type treeDir struct {
+	path   string
+	stamps map[string]fileStamp
+	files  []*parsedFile
+}
+
+// fileStamp identifies version of a file without reading it.
+type fileStamp struct {
+	size    int64
+	modTime int64
+}
+
+// NewTree creates empty tree of the directory, call Refresh to parse it.
+func NewTree(dir string, excluded []string) *Tree {
+	return &Tree{dir: dir, excluded: excluded}
+}
+
+// Refresh walks the directory tree and parses directories changed since
+// the previous refresh. It reports whether any directory was parsed, added
+// or removed. On error the tree is left unchanged, so the next refresh
+// tries to parse the same directories again.
+func (t *Tree) Refresh() (bool, error) {
+	previous := make(map[string]*treeDir, len(t.dirs))
+	for _, dir := range t.dirs {
+		previous[dir.path] = dir
+	}
+
+	changed := false
+	reused := 0
+	dirs := make([]*treeDir, 0, len(t.dirs))
+	err := filepath.Walk(t.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if shouldSkip(info.Name(), t.excluded) {
+			return filepath.SkipDir
+		}
+
+		if info.IsDir() {
+			stamps, err := dirStamps(path)
+			if err != nil {
+				return err
+			}
+			if old, ok := previous[path]; ok && sameStamps(old.stamps, stamps) {
+				dirs = append(dirs, old)
+				reused++
+				return nil
+			}
+			files, err := parseDir(path, t.cache)
+			if err != nil {
+				return err
+			}
+			dirs = append(dirs, &treeDir{path: path, stamps: stamps, files: files})
+			changed = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if reused < len(previous) {
+		changed = true
+	}
+	t.dirs = dirs
+	return changed, nil
+}
+
+// Packages builds list of packages from parsed files. Import paths are resolved
+// with current go.mod files. Packages are new on each call, so they may be modified.
+func (t *Tree) Packages() []*model.Pkg {
+	packages := make([]*model.Pkg, 0, 16)
+	mods := newModules()
+	for _, dir := range t.dirs {
+		importPath, module := mods.importPath(dir.path)
+		packages = append(packages, newPackages(dir.files, dir.path, importPath, module)...)
+	}
+	classifyImports(packages, mods.paths())
+	return packages
+}
+
+// dirStamps returns stamps of .go files and go.mod file of the directory.
+func dirStamps(dir string) (map[string]fileStamp, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	stamps := make(map[string]fileStamp, len(infos))
+	for _, info := range infos {
+		if info.IsDir() || !(strings.HasSuffix(info.Name(), ".go") || info.Name() == "go.mod") {
+			continue
+		}
+		stamps[info.Name()] = fileStamp{size: info.Size(), modTime: info.ModTime().UnixNano()}
+	}
+	return stamps, nil
+}
+
+func sameStamps(left, right map[string]fileStamp) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	for name, stamp := range left {
+		if other, ok := right[name]; !ok || other != stamp {
+			return false
+		}
+	}
+	return true
+}
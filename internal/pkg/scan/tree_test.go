@@ -0,0 +1,118 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anticycle/anticycle/internal/pkg/fixtures"
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func treeDirFiles(tree *Tree, name string) []*parsedFile {
+	for _, dir := range tree.dirs {
+		if filepath.Base(dir.path) == name {
+			return dir.files
+		}
+	}
+	return nil
+}
+
+func pkgNames(packages []*model.Pkg) []string {
+	names := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		names = append(names, pkg.Name)
+	}
+	return names
+}
+
+func TestTree_Refresh(t *testing.T) {
+	dir, remove := makeProjectOneToOne("treeRefresh")
+	defer remove()
+
+	tree := NewTree(dir, []string{})
+	changed, err := tree.Refresh()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{"bar", "baz", "foo"}, pkgNames(tree.Packages()))
+
+	changed, err = tree.Refresh()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	foo := treeDirFiles(tree, "foo")
+	bar := treeDirFiles(tree, "bar")
+	err = fixtures.WriteFile(filepath.Join(dir, "bar"), "bar.go", "package bar\nimport \"fmt\"")
+	assert.NoError(t, err)
+
+	changed, err = tree.Refresh()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, foo[0] == treeDirFiles(tree, "foo")[0], "unchanged directory should not be parsed again")
+	assert.False(t, bar[0] == treeDirFiles(tree, "bar")[0], "changed directory should be parsed again")
+	assert.Equal(t, "fmt", treeDirFiles(tree, "bar")[0].Imports[0].Name)
+}
+
+func TestTree_Refresh_WhenDirectoryAddedOrRemoved(t *testing.T) {
+	dir, remove := makeProjectOneToOne("treeAddRemove")
+	defer remove()
+
+	tree := NewTree(dir, []string{})
+	_, err := tree.Refresh()
+	assert.NoError(t, err)
+
+	err = fixtures.WriteFile(filepath.Join(dir, "qux"), "qux.go", "package qux")
+	assert.NoError(t, err)
+	changed, err := tree.Refresh()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{"bar", "baz", "foo", "qux"}, pkgNames(tree.Packages()))
+
+	err = os.RemoveAll(filepath.Join(dir, "foo"))
+	assert.NoError(t, err)
+	changed, err = tree.Refresh()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{"bar", "baz", "qux"}, pkgNames(tree.Packages()))
+}
+
+func TestTree_Refresh_WhenSyntaxError(t *testing.T) {
+	dir, remove := makeProjectOneToOne("treeSyntaxError")
+	defer remove()
+
+	tree := NewTree(dir, []string{})
+	_, err := tree.Refresh()
+	assert.NoError(t, err)
+
+	err = fixtures.WriteFile(filepath.Join(dir, "bar"), "bar.go", "package bar\nimport (")
+	assert.NoError(t, err)
+	changed, err := tree.Refresh()
+	assert.Error(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, []string{"bar", "baz", "foo"}, pkgNames(tree.Packages()))
+
+	err = fixtures.WriteFile(filepath.Join(dir, "bar"), "bar.go", "package bar")
+	assert.NoError(t, err)
+	changed, err = tree.Refresh()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Empty(t, tree.Packages()[0].Imports)
+}
+
+func TestTree_Packages_WhenModified(t *testing.T) {
+	dir, remove := makeProjectOneToOne("treePackages")
+	defer remove()
+
+	tree := NewTree(dir, []string{})
+	_, err := tree.Refresh()
+	assert.NoError(t, err)
+
+	packages := tree.Packages()
+	packages[0].Files[0].Imports = nil
+	assert.NotEmpty(t, tree.Packages()[0].Files[0].Imports)
+}
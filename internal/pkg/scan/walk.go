@@ -5,12 +5,16 @@
 package scan
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/anticycle/anticycle/pkg/model"
 )
@@ -23,52 +27,129 @@ func shouldSkip(pathName string, excluded []string) bool {
 	return false
 }
 
-func newPackages(root map[string]*ast.Package, path string) []*model.Pkg {
-	packages := make([]*model.Pkg, 0)
-
-	for name, astPkg := range root {
-		pkg := model.NewPkg()
-		pkg.Name = name
-		pkg.Path = path
+// parsedFile holds package name, imports and suppression directives of a source file.
+type parsedFile struct {
+	Path         string               `json:"path"`
+	Package      string               `json:"package"`
+	Imports      []*model.ImportInfo  `json:"imports"`
+	Suppressions []*model.Suppression `json:"suppressions"`
+}
 
-		for path, astFile := range astPkg.Files {
-			file := model.NewFile()
-			file.Path = path
+func newParsedFile(fset *token.FileSet, path string, astFile *ast.File) *parsedFile {
+	file := &parsedFile{
+		Path:    path,
+		Package: astFile.Name.Name,
+		Imports: make([]*model.ImportInfo, 0, len(astFile.Imports)),
+	}
+	for _, importSpec := range astFile.Imports {
+		importInfo := model.NewImportInfo(importSpec)
+		importInfo.Line = fset.Position(importSpec.Pos()).Line
+		file.Imports = append(file.Imports, importInfo)
 
-			for _, importSpec := range astFile.Imports {
-				importInfo := model.NewImportInfo(importSpec)
-				file.Imports = append(file.Imports, importInfo)
-				pkg.Imports[importInfo.Name] = importInfo
-			}
-			pkg.Files = append(pkg.Files, file)
+		if reason, ok := ignoreDirective(importSpec); ok {
+			file.Suppressions = append(file.Suppressions, &model.Suppression{
+				Pkg:       file.Package,
+				File:      path,
+				Line:      importInfo.Line,
+				Directive: model.DirectiveIgnore,
+				Import:    importInfo.Name,
+				Reason:    reason,
+			})
 		}
-		packages = append(packages, pkg)
 	}
-	return packages
+	file.Suppressions = append(file.Suppressions, allowCycleDirectives(fset, file.Package, path, astFile)...)
+	return file
+}
+
+// parseFile parses imports and comments of the file, unless the cache holds the same version of the file.
+func parseFile(path string, info os.FileInfo, cache *Cache) (*parsedFile, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(src)
+	hash := hex.EncodeToString(sum[:])
+	if file := cache.get(path, info, hash); file != nil {
+		return file, nil
+	}
+
+	fset := token.NewFileSet()
+	// Comments are parsed to find suppression directives.
+	astFile, err := parser.ParseFile(fset, path, src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	file := newParsedFile(fset, path, astFile)
+	cache.put(path, info, hash, file)
+	return file, nil
 }
 
-func walkDir(dir string, excluded []string) ([]*model.Pkg, error) {
-	packages := make([]*model.Pkg, 0, 16)
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+// parseDir parses all .go files of the directory, sorted by name, like parser.ParseDir.
+func parseDir(dir string, cache *Cache) ([]*parsedFile, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]*parsedFile, 0, len(infos))
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".go") {
+			continue
+		}
+		file, err := parseFile(filepath.Join(dir, info.Name()), info, cache)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		files = append(files, file)
+	}
+	return files, nil
+}
 
-		if shouldSkip(info.Name(), excluded) {
-			return filepath.SkipDir
-		}
+// newPackages groups files of the directory by package name. Parsed files
+// may be cached, so they are copied.
+func newPackages(files []*parsedFile, path, importPath, module string) []*model.Pkg {
+	packages := make([]*model.Pkg, 0)
+	byName := make(map[string]*model.Pkg)
 
-		if info.IsDir() {
-			fset := token.NewFileSet()
-			parsedDir, err := parser.ParseDir(fset, path, nil, parser.ImportsOnly)
-			if err != nil {
-				return err
-			}
-			packages = append(packages, newPackages(parsedDir, path)...)
+	for _, parsed := range files {
+		pkg, ok := byName[parsed.Package]
+		if !ok {
+			pkg = model.NewPkg()
+			pkg.Name = parsed.Package
+			pkg.Path = path
+			pkg.ImportPath = importPath
+			pkg.Module = module
+			byName[parsed.Package] = pkg
+			packages = append(packages, pkg)
 		}
 
-		return nil
+		file := model.NewFile()
+		file.Path = parsed.Path
+		for _, imp := range parsed.Imports {
+			fileImport := *imp
+			file.Imports = append(file.Imports, &fileImport)
+			// Line is specific to the file, so package holds import without it.
+			pkgImport := *imp
+			pkgImport.Line = 0
+			pkg.Imports[imp.Name] = &pkgImport
+		}
+		for _, suppression := range parsed.Suppressions {
+			pkgSuppression := *suppression
+			pkg.Suppressions = append(pkg.Suppressions, &pkgSuppression)
+		}
+		pkg.Files = append(pkg.Files, file)
+	}
+	sort.SliceStable(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
 	})
+	return packages
+}
 
-	return packages, err
+// walkDir parses recursively given directory skipping excluded directories
+// and builds list of packages. It is a single refresh of a new tree.
+func walkDir(dir string, excluded []string, cache *Cache) ([]*model.Pkg, error) {
+	tree := &Tree{dir: dir, excluded: excluded, cache: cache}
+	if _, err := tree.Refresh(); err != nil {
+		return nil, err
+	}
+	return tree.Packages(), nil
 }
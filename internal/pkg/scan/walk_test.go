@@ -5,9 +5,11 @@
 package scan
 
 import (
-	"go/ast"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/anticycle/anticycle/internal/pkg/fixtures"
 	"github.com/anticycle/anticycle/pkg/model"
 	"github.com/stretchr/testify/assert"
 )
@@ -41,31 +43,52 @@ func TestMakePackages(t *testing.T) {
 		},
 	}
 
-	root := map[string]*ast.Package{
-		"foo": {
-			Files: map[string]*ast.File{
-				"internal/pkg/foo/foo.go": {
-					Imports: []*ast.ImportSpec{{Path: &ast.BasicLit{Value: `"pkg/foo"`}}},
-				},
-			},
+	files := []*parsedFile{
+		{
+			Path:    "internal/pkg/foo/foo.go",
+			Package: "foo",
+			Imports: []*model.ImportInfo{{Name: "pkg/foo", NameShort: "foo", Alias: nil}},
 		},
 	}
-	packages := newPackages(root, "internal/pkg/foo")
+	packages := newPackages(files, "internal/pkg/foo", "", "")
 	assert.EqualValues(t, expected, packages)
 }
 
 func TestMakePackages_WithEmptyRoot(t *testing.T) {
-	root := make(map[string]*ast.Package)
-	packages := newPackages(root, "testpath")
+	packages := newPackages([]*parsedFile{}, "testpath", "", "")
 	assert.Len(t, packages, 0)
 }
 
+func TestParseFile(t *testing.T) {
+	dir, remove := tmpDir("parseFile")
+	defer remove()
+	err := fixtures.WriteFile(dir, "foo.go", "package foo\n\nimport (\n\tb \"pkg/bar\" //anticycle:ignore reason\n)\n")
+	assert.NoError(t, err)
+
+	path := filepath.Join(dir, "foo.go")
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	file, err := parseFile(path, info, nil)
+	assert.NoError(t, err)
+
+	alias := "b"
+	expected := &parsedFile{
+		Path:    path,
+		Package: "foo",
+		Imports: []*model.ImportInfo{{Name: "pkg/bar", NameShort: "bar", Alias: &alias, Line: 4}},
+		Suppressions: []*model.Suppression{
+			{Pkg: "foo", File: path, Line: 4, Directive: model.DirectiveIgnore, Import: "pkg/bar", Reason: "reason"},
+		},
+	}
+	assert.Equal(t, expected, file)
+}
+
 func TestWalkDir(t *testing.T) {
 	dir, remove := makeProjectNoCycles("walkDir")
 	defer remove()
 
 	expected := []string{"bar", "baz", "foo"}
-	packages, err := walkDir(dir, []string{})
+	packages, err := walkDir(dir, []string{}, nil)
 	assert.NoError(t, err)
 
 	result := make([]string, 0)
@@ -80,7 +103,7 @@ func TestWalkDir_WithExcludedDirs(t *testing.T) {
 	defer remove()
 	expected := []string{"baz", "foo"}
 
-	packages, err := walkDir(dir, []string{"bar"})
+	packages, err := walkDir(dir, []string{"bar"}, nil)
 	assert.NoError(t, err)
 
 	result := make([]string, 0)
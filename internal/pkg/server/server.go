@@ -0,0 +1,239 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+// Package server serves analysis of a directory over HTTP, as JSON API
+// and interactive graph of packages.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/anticycle/anticycle/internal/pkg/scan"
+	"github.com/anticycle/anticycle/pkg/anticycle"
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/anticycle/anticycle/pkg/serialize"
+)
+
+// Server keeps parsed files of the directory in memory and serves:
+//
+//	GET  /           interactive graph of all packages, like -format=html -all
+//	GET  /packages   all packages with cycles marked, as JSON list of model.Pkg
+//	GET  /cycles     analysis of packages in cycles, like -format=json
+//	GET  /why        the shortest chain of imports between packages given with
+//	                 from and to parameters, as model.ImportChain
+//	GET  /metrics    stability of packages, as model.StabilityReport
+//	POST /rescan     parses changed directories and responds like /cycles
+//
+// Errors are sent as JSON object with error field.
+type Server struct {
+	// Tool identifies anticycle build in the analysis.
+	Tool *model.Tool
+
+	dir      string
+	excluded []string
+	kinds    []string
+	tree     *scan.Tree
+	mux      *http.ServeMux
+
+	mu        sync.RWMutex
+	packages  []*model.Pkg
+	analysis  *model.Analysis
+	stability *model.StabilityReport
+}
+
+// NewServer creates server of the directory, which skips excluded directories and
+// analyzes only imports of given kinds, all kinds if the list is empty.
+// Call Rescan to scan the directory before serving.
+func NewServer(dir string, excluded, kinds []string) *Server {
+	s := &Server{
+		dir:      dir,
+		excluded: excluded,
+		kinds:    kinds,
+		tree:     scan.NewTree(dir, excluded),
+		mux:      http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/", s.handleGraph)
+	s.mux.HandleFunc("/packages", s.handlePackages)
+	s.mux.HandleFunc("/cycles", s.handleCycles)
+	s.mux.HandleFunc("/why", s.handleWhy)
+	s.mux.HandleFunc("/metrics", s.handleMetrics)
+	s.mux.HandleFunc("/rescan", s.handleRescan)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Rescan parses directories changed since the previous scan and analyzes
+// all packages again. On error the previous analysis is kept.
+func (s *Server) Rescan() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed, err := s.tree.Refresh()
+	if err != nil || (!changed && s.analysis != nil) {
+		return err
+	}
+	packages, suppressions, err := s.findCycles()
+	if err != nil {
+		return err
+	}
+	now, err := anticycle.Timestamp()
+	if err != nil {
+		return err
+	}
+
+	// Filtering of affected packages modifies them, so copies are filtered.
+	analysis := anticycle.Analyze(anticycle.OnlyAffected(anticycle.ClonePackages(packages)))
+	analysis.Tool = s.Tool
+	analysis.Root = s.dir
+	analysis.Timestamp = now.Format(time.RFC3339)
+	analysis.Options = &model.Options{Excluded: s.excluded, Kinds: s.kinds}
+	if len(suppressions.Active) > 0 || len(suppressions.Stale) > 0 {
+		analysis.Suppressions = suppressions
+	}
+	s.packages = packages
+	s.analysis = analysis
+	s.stability = anticycle.Stability(packages)
+	return nil
+}
+
+// findCycles builds packages from parsed files and marks cycles.
+func (s *Server) findCycles() ([]*model.Pkg, *model.SuppressionReport, error) {
+	packages := s.tree.Packages()
+	var err error
+	if len(s.kinds) > 0 {
+		packages, err = anticycle.FilterKinds(packages, s.kinds)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	suppressions := anticycle.Suppress(packages)
+	packages, err = anticycle.FindCycles(packages)
+	return packages, suppressions, err
+}
+
+func (s *Server) handleGraph(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("path '%v' is not found", r.URL.Path))
+		return
+	}
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	s.mu.RLock()
+	output, err := serialize.ToHTML(anticycle.Analyze(s.packages))
+	s.mu.RUnlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	io.WriteString(w, output)
+}
+
+func (s *Server) handlePackages(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	writeJSON(w, s.packages)
+}
+
+func (s *Server) handleCycles(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	s.writeAnalysis(w)
+}
+
+func (s *Server) handleWhy(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+	if from == "" || to == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("why requires 'from' and 'to' parameters"))
+		return
+	}
+	s.mu.RLock()
+	chain, err := anticycle.Why(s.packages, from, to)
+	s.mu.RUnlock()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if chain == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("package '%v' does not depend on '%v'", from, to))
+		return
+	}
+	writeJSON(w, chain)
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	writeJSON(w, s.stability)
+}
+
+func (s *Server) handleRescan(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	if err := s.Rescan(); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeAnalysis(w)
+}
+
+func (s *Server) writeAnalysis(w http.ResponseWriter) {
+	s.mu.RLock()
+	output, err := serialize.ToJSON(s.analysis)
+	s.mu.RUnlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, output)
+}
+
+// allowMethod responds with an error if request method is other than given.
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method '%v' is not allowed, use '%v'", r.Method, method))
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(content)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	content, _ := json.Marshal(map[string]string{"error": err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(content)
+}
@@ -0,0 +1,171 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anticycle/anticycle/internal/pkg/fixtures"
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func startServer(t *testing.T) (string, *httptest.Server, func()) {
+	dir, err := ioutil.TempDir("", "anticycle-server")
+	assert.NoError(t, err)
+	assert.NoError(t, fixtures.WriteSource(dir, "foo", fmt.Sprintf("package foo\nimport \"%s/bar\"", dir)))
+	assert.NoError(t, fixtures.WriteSource(dir, "bar", fmt.Sprintf("package bar\nimport \"%s/baz\"", dir)))
+	assert.NoError(t, fixtures.WriteSource(dir, "baz", fmt.Sprintf("package baz\nimport \"%s/bar\"", dir)))
+
+	srv := NewServer(dir, []string{}, []string{})
+	srv.Tool = &model.Tool{Name: "anticycle", Version: "1.0.0", Build: "test"}
+	assert.NoError(t, srv.Rescan())
+	ts := httptest.NewServer(srv)
+	return dir, ts, func() {
+		ts.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func request(t *testing.T, method, url string, v interface{}) (int, string) {
+	req, err := http.NewRequest(method, url, nil)
+	assert.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	if v != nil {
+		assert.NoError(t, json.Unmarshal(body, v))
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestServer_Packages(t *testing.T) {
+	_, ts, stop := startServer(t)
+	defer stop()
+
+	packages := make([]*model.Pkg, 0)
+	status, _ := request(t, http.MethodGet, ts.URL+"/packages", &packages)
+	assert.Equal(t, http.StatusOK, status)
+	names := make([]string, 0)
+	for _, pkg := range packages {
+		names = append(names, fmt.Sprintf("%s:%v", pkg.Name, pkg.HaveCycle))
+	}
+	assert.Equal(t, []string{"bar:true", "baz:true", "foo:false"}, names)
+}
+
+func TestServer_Cycles(t *testing.T) {
+	dir, ts, stop := startServer(t)
+	defer stop()
+
+	analysis := &model.Analysis{}
+	status, _ := request(t, http.MethodGet, ts.URL+"/cycles", analysis)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, model.SchemaVersion, analysis.SchemaVersion)
+	assert.Equal(t, "1.0.0", analysis.Tool.Version)
+	assert.Equal(t, dir, analysis.Root)
+	assert.Equal(t, [][]string{{"bar", "baz", "bar"}, {"baz", "bar", "baz"}}, analysis.Metadata.Cycles)
+	assert.Len(t, analysis.Cycles, 2)
+}
+
+func TestServer_Cycles_WithSourceDateEpoch(t *testing.T) {
+	os.Setenv("SOURCE_DATE_EPOCH", "1514764800")
+	defer os.Unsetenv("SOURCE_DATE_EPOCH")
+	_, ts, stop := startServer(t)
+	defer stop()
+
+	analysis := &model.Analysis{}
+	status, _ := request(t, http.MethodGet, ts.URL+"/cycles", analysis)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "2018-01-01T00:00:00Z", analysis.Timestamp)
+}
+
+func TestServer_Why(t *testing.T) {
+	dir, ts, stop := startServer(t)
+	defer stop()
+
+	chain := &model.ImportChain{}
+	status, _ := request(t, http.MethodGet, ts.URL+"/why?from=foo&to=baz", chain)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{filepath.Join(dir, "foo"), filepath.Join(dir, "bar"), filepath.Join(dir, "baz")}, chain.Packages)
+	assert.Equal(t, filepath.Join(dir, "foo", "foo.go"), chain.Links[0].File)
+	assert.Equal(t, 2, chain.Links[0].Import.Line)
+
+	cycle := &model.ImportChain{}
+	status, _ = request(t, http.MethodGet, ts.URL+"/why?from=bar&to=bar", cycle)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{filepath.Join(dir, "bar"), filepath.Join(dir, "baz"), filepath.Join(dir, "bar")}, cycle.Packages)
+
+	tests := []struct {
+		query, expected string
+		status          int
+	}{
+		{"from=foo", `{"error":"why requires 'from' and 'to' parameters"}`, http.StatusBadRequest},
+		{"from=foo&to=qux", `{"error":"package 'qux' is not found"}`, http.StatusBadRequest},
+		{"from=baz&to=foo", `{"error":"package 'baz' does not depend on 'foo'"}`, http.StatusNotFound},
+	}
+	for _, test := range tests {
+		status, body := request(t, http.MethodGet, ts.URL+"/why?"+test.query, nil)
+		assert.Equal(t, test.status, status, test.query)
+		assert.Equal(t, test.expected, body, test.query)
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	_, ts, stop := startServer(t)
+	defer stop()
+
+	report := &model.StabilityReport{}
+	status, _ := request(t, http.MethodGet, ts.URL+"/metrics", report)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, report.Packages, 3)
+}
+
+func TestServer_Graph(t *testing.T) {
+	_, ts, stop := startServer(t)
+	defer stop()
+
+	status, body := request(t, http.MethodGet, ts.URL+"/", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.True(t, strings.HasPrefix(body, "<!DOCTYPE html>"))
+	assert.Contains(t, body, `"cycles":[["bar","baz","bar"]`)
+
+	status, body = request(t, http.MethodGet, ts.URL+"/unknown", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, `{"error":"path '/unknown' is not found"}`, body)
+}
+
+func TestServer_Rescan(t *testing.T) {
+	dir, ts, stop := startServer(t)
+	defer stop()
+
+	status, body := request(t, http.MethodGet, ts.URL+"/rescan", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+	assert.Equal(t, `{"error":"method 'GET' is not allowed, use 'POST'"}`, body)
+
+	assert.NoError(t, fixtures.WriteSource(dir, "baz", "package baz\nimport ("))
+	status, body = request(t, http.MethodPost, ts.URL+"/rescan", nil)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Contains(t, body, "expected ')'")
+
+	assert.NoError(t, fixtures.WriteSource(dir, "baz", "package baz"))
+	analysis := &model.Analysis{}
+	status, _ = request(t, http.MethodPost, ts.URL+"/rescan", analysis)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, analysis.Metadata.Cycles)
+
+	status, _ = request(t, http.MethodGet, ts.URL+"/cycles", analysis)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, analysis.Metadata.Cycles)
+}
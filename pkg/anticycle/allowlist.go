@@ -0,0 +1,88 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// dateLayout is a layout of expiry dates of allowed cycles.
+const dateLayout = "2006-01-02"
+
+// ParseConfig reads JSON configuration of the analysis.
+// Will return error if any allowed cycle has no packages, owner or valid expiry date.
+func ParseConfig(data []byte) (*model.Config, error) {
+	config := &model.Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	if config.AllowedCycles == nil {
+		config.AllowedCycles = make([]*model.AllowedCycle, 0)
+	}
+	for _, allowed := range config.AllowedCycles {
+		if len(allowed.Cycle) == 0 || allowed.Owner == "" || allowed.Expires == "" {
+			return nil, fmt.Errorf("allowed cycle %v must have packages, owner and expires date", allowed.Cycle)
+		}
+		if _, err := time.Parse(dateLayout, allowed.Expires); err != nil {
+			return nil, fmt.Errorf("allowed cycle %v expires='%v' is not a date in YYYY-MM-DD format", allowed.Cycle, allowed.Expires)
+		}
+	}
+	return config, nil
+}
+
+// Allowlist compares cycles of the analysis with allowed cycles. Cycles are compared
+// regardless of package they start from. Allowed cycle expires after its expiry date,
+// compared with given time in UTC.
+func Allowlist(analysis *model.Analysis, allowedCycles []*model.AllowedCycle, now time.Time) *model.AllowlistReport {
+	report := &model.AllowlistReport{
+		Allowed:    make([]*model.AllowedCycle, 0),
+		Expired:    make([]*model.AllowedCycle, 0),
+		NotAllowed: make([][]string, 0),
+		Unused:     make([]*model.AllowedCycle, 0),
+	}
+	today := now.UTC().Format(dateLayout)
+
+	allowedKeys := make(map[string]*model.AllowedCycle, len(allowedCycles))
+	for _, allowed := range allowedCycles {
+		allowedKeys[allowedKey(allowed.Cycle)] = allowed
+	}
+	found := make(map[string]bool)
+	for _, cycle := range UniqueCycles(analysis.Metadata.Cycles) {
+		key := model.CycleKey(cycle)
+		allowed, ok := allowedKeys[key]
+		switch {
+		case !ok:
+			report.NotAllowed = append(report.NotAllowed, cycle)
+		case allowed.Expires < today:
+			report.Expired = append(report.Expired, allowed)
+		default:
+			report.Allowed = append(report.Allowed, allowed)
+		}
+		found[key] = true
+	}
+	for _, allowed := range allowedCycles {
+		if !found[allowedKey(allowed.Cycle)] {
+			report.Unused = append(report.Unused, allowed)
+		}
+	}
+	return report
+}
+
+// allowedKey identifies allowed cycle, which may be given with or without
+// the first package repeated at the end, e.g. [bar baz bar] or [bar baz].
+func allowedKey(packages []string) string {
+	if len(packages) == 0 {
+		return ""
+	}
+	cycle := append([]string{}, packages...)
+	if cycle[0] != cycle[len(cycle)-1] || len(cycle) == 1 {
+		cycle = append(cycle, cycle[0])
+	}
+	return model.CycleKey(cycle)
+}
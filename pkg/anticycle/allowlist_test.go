@@ -0,0 +1,74 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte(`{"allowedCycles":[{"cycle":["bar","baz"],"owner":"core","expires":"2018-03-01"}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.AllowedCycle{{Cycle: []string{"bar", "baz"}, Owner: "core", Expires: "2018-03-01"}}, config.AllowedCycles)
+
+	config, err = ParseConfig([]byte(`{}`))
+	assert.NoError(t, err)
+	assert.Empty(t, config.AllowedCycles)
+}
+
+func TestParseConfig_WithInvalidAllowedCycle(t *testing.T) {
+	tests := []struct {
+		name, input, expected string
+	}{
+		{
+			name:     "Without owner",
+			input:    `{"allowedCycles":[{"cycle":["bar","baz"],"expires":"2018-03-01"}]}`,
+			expected: "allowed cycle [bar baz] must have packages, owner and expires date",
+		},
+		{
+			name:     "With invalid date",
+			input:    `{"allowedCycles":[{"cycle":["bar","baz"],"owner":"core","expires":"01.03.2018"}]}`,
+			expected: "allowed cycle [bar baz] expires='01.03.2018' is not a date in YYYY-MM-DD format",
+		},
+		{
+			name:     "Invalid JSON",
+			input:    `{"allowedCycles":{}}`,
+			expected: "json: cannot unmarshal object into Go struct field Config.allowedCycles of type []*model.AllowedCycle",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(test.input))
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestAllowlist(t *testing.T) {
+	analysis := &model.Analysis{
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{
+			{"bar", "baz", "bar"},
+			{"baz", "bar", "baz"},
+			{"foo", "qux", "foo"},
+			{"one", "two", "one"},
+		}},
+	}
+	allowed := &model.AllowedCycle{Cycle: []string{"baz", "bar"}, Owner: "core", Expires: "2018-01-01"}
+	expired := &model.AllowedCycle{Cycle: []string{"qux", "foo", "qux"}, Owner: "api", Expires: "2017-12-31"}
+	unused := &model.AllowedCycle{Cycle: []string{"old", "new"}, Owner: "api", Expires: "2018-06-01"}
+	now := time.Date(2018, 1, 1, 23, 0, 0, 0, time.UTC)
+
+	report := Allowlist(analysis, []*model.AllowedCycle{allowed, expired, unused}, now)
+
+	assert.Equal(t, []*model.AllowedCycle{allowed}, report.Allowed)
+	assert.Equal(t, []*model.AllowedCycle{expired}, report.Expired)
+	assert.Equal(t, [][]string{{"one", "two", "one"}}, report.NotAllowed)
+	assert.Equal(t, []*model.AllowedCycle{unused}, report.Unused)
+}
@@ -6,8 +6,12 @@
 package anticycle
 
 import (
+	"fmt"
+	"os"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/anticycle/anticycle/internal/pkg/scan"
 	"github.com/anticycle/anticycle/pkg/model"
@@ -24,6 +28,11 @@ var DefaultExcluded = []string{
 	"vendor",
 }
 
+// CacheDir is a directory where Fetch keeps parsed files, so unchanged files
+// are not parsed again. Cache is disabled if CacheDir is empty. Cache should be
+// separate for each version of anticycle, because parsed data may change.
+var CacheDir = ""
+
 // ExcludeDirs takes list of directories excluded by default and appends directories defined by user.
 // Directories should have only basic, catalogue names, see example.
 func ExcludeDirs(custom []string) []string {
@@ -38,12 +47,12 @@ func ExcludeDirs(custom []string) []string {
 // Collect will not compile the code. It looks only at imports in AST data.
 func Collect(dir string, excludedDir []string, all bool) (result []*model.Pkg, err error) {
 	// TODO: make integration tests
-	packages, err := scan.FetchPackages(dir, excludedDir)
+	packages, err := Fetch(dir, excludedDir)
 	if err != nil {
 		return result, err
 	}
 
-	cycles, err := scan.FindCycles(packages)
+	cycles, err := FindCycles(packages)
 	if err != nil {
 		return result, err
 	}
@@ -51,11 +60,25 @@ func Collect(dir string, excludedDir []string, all bool) (result []*model.Pkg, e
 	if all {
 		result = cycles
 	} else {
-		result = onlyAffected(cycles)
+		result = OnlyAffected(cycles)
 	}
 	return result, err
 }
 
+// Fetch parses recursively all .go files skipping excluded directories
+// and builds list of packages without looking for cycles.
+// Packages may be modified before they are passed to FindCycles.
+// Parsed files are cached in CacheDir.
+func Fetch(dir string, excludedDir []string) ([]*model.Pkg, error) {
+	return scan.FetchPackages(dir, excludedDir, CacheDir)
+}
+
+// FindCycles marks packages which take part in cycles
+// and captures affected files and imports.
+func FindCycles(packages []*model.Pkg) ([]*model.Pkg, error) {
+	return scan.FindCycles(packages)
+}
+
 // Analyze goes through collected packages and computes metadata.
 func Analyze(packages []*model.Pkg) *model.Analysis {
 	if packages == nil {
@@ -71,31 +94,49 @@ func Analyze(packages []*model.Pkg) *model.Analysis {
 		return analysis
 	}
 
-	cycleRefs := make(map[string][]string, len(packages))
-	for _, pkg := range packages {
+	// Packages may share a name and imports may differ from package names,
+	// so cycles are followed by index of the imported package.
+	idx := scan.NewIndex(packages)
+	cycleRefs := make([][]int, len(packages))
+	for i, pkg := range packages {
 		if !pkg.HaveCycle {
 			continue
 		}
-
-		if _, ok := cycleRefs[pkg.Name]; !ok {
-			cycleRefs[pkg.Name] = make([]string, 0, len(pkg.Cycles))
-		}
 		for _, cycle := range pkg.Cycles {
-			cycleRefs[pkg.Name] = append(cycleRefs[pkg.Name], cycle.AffectedImport.NameShort)
+			if next := idx.Find(cycle.AffectedImport); next >= 0 {
+				cycleRefs[i] = append(cycleRefs[i], next)
+			}
 		}
 	}
-	if len(cycleRefs) > 0 {
-		for from := range cycleRefs {
-			visited := make([]string, 0)
-			visited = append(visited, walk(from, cycleRefs, visited)...)
-			analysis.Metadata.Cycles = append(analysis.Metadata.Cycles, visited)
+	for from := range packages {
+		if len(cycleRefs[from]) == 0 {
+			continue
 		}
-		analysis.Metadata.Cycles = sortMetaCycles(analysis.Metadata.Cycles)
+		visited := make([]string, 0)
+		for _, node := range walk(from, cycleRefs, make([]int, 0)) {
+			visited = append(visited, packages[node].Name)
+		}
+		analysis.Metadata.Cycles = append(analysis.Metadata.Cycles, visited)
 	}
+	analysis.Metadata.Cycles = sortMetaCycles(analysis.Metadata.Cycles)
 
 	return analysis
 }
 
+// Timestamp returns current time of the analysis, or time given in SOURCE_DATE_EPOCH
+// environment variable to make the output reproducible.
+func Timestamp() (time.Time, error) {
+	now := time.Now()
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return now, fmt.Errorf("SOURCE_DATE_EPOCH='%v' is not a number of seconds", epoch)
+		}
+		now = time.Unix(seconds, 0)
+	}
+	return now.UTC(), nil
+}
+
 // To provide deterministic output sort metadata cycles by first element of each cycle.
 func sortMetaCycles(metaCycles [][]string) [][]string {
 	sort.SliceStable(metaCycles, func(i, j int) bool {
@@ -109,7 +150,9 @@ func sortMetaCycles(metaCycles [][]string) [][]string {
 	return metaCycles
 }
 
-func walk(next string, refs map[string][]string, visited []string) []string {
+// walk follows the first cycle reference of each package until it comes back
+// to a visited package. Walk stops at a package without references.
+func walk(next int, refs [][]int, visited []int) []int {
 	for _, vi := range visited {
 		if vi == next {
 			visited = append(visited, next)
@@ -117,10 +160,16 @@ func walk(next string, refs map[string][]string, visited []string) []string {
 		}
 	}
 	visited = append(visited, next)
+	if len(refs[next]) == 0 {
+		return visited
+	}
 	return walk(refs[next][0], refs, visited)
 }
 
-func onlyAffected(packages []*model.Pkg) []*model.Pkg {
+// OnlyAffected filters packages with cycles and narrows theirs imports
+// and files to those which take part in the cycle.
+// Given packages are modified in place.
+func OnlyAffected(packages []*model.Pkg) []*model.Pkg {
 	var result []*model.Pkg
 	for _, pkg := range packages {
 		if pkg.HaveCycle {
@@ -157,3 +206,44 @@ func onlyAffected(packages []*model.Pkg) []*model.Pkg {
 	}
 	return result
 }
+
+// ClonePackages copies packages with files, imports, cycles and suppressions,
+// so copies may be modified, e.g. by OnlyAffected, leaving given packages intact.
+func ClonePackages(packages []*model.Pkg) []*model.Pkg {
+	clones := make([]*model.Pkg, 0, len(packages))
+	for _, pkg := range packages {
+		clone := model.NewPkg()
+		clone.Name = pkg.Name
+		clone.Path = pkg.Path
+		clone.ImportPath = pkg.ImportPath
+		clone.Module = pkg.Module
+		clone.HaveCycle = pkg.HaveCycle
+		for name, imp := range pkg.Imports {
+			pkgImport := *imp
+			clone.Imports[name] = &pkgImport
+		}
+		for _, file := range pkg.Files {
+			cloneFile := model.NewFile()
+			cloneFile.Path = file.Path
+			for _, imp := range file.Imports {
+				fileImport := *imp
+				cloneFile.Imports = append(cloneFile.Imports, &fileImport)
+			}
+			clone.Files = append(clone.Files, cloneFile)
+		}
+		for _, cycle := range pkg.Cycles {
+			affectedImport := *cycle.AffectedImport
+			clone.Cycles = append(clone.Cycles, &model.Cycle{
+				AffectedImport: &affectedImport,
+				AffectedFile:   cycle.AffectedFile,
+				Owners:         append([]string(nil), cycle.Owners...),
+			})
+		}
+		for _, suppression := range pkg.Suppressions {
+			cloneSuppression := *suppression
+			clone.Suppressions = append(clone.Suppressions, &cloneSuppression)
+		}
+		clones = append(clones, clone)
+	}
+	return clones
+}
@@ -6,7 +6,9 @@ package anticycle
 
 import (
 	"fmt"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/anticycle/anticycle/pkg/model"
 	"github.com/stretchr/testify/assert"
@@ -137,7 +139,7 @@ func TestAnticycle_filterAffected(t *testing.T) {
 			},
 		},
 	}
-	result := onlyAffected(packages)
+	result := OnlyAffected(packages)
 	assert.Len(t, result, 2)
 
 	for _, pkg := range result {
@@ -202,3 +204,70 @@ func TestSortMetaCycles(t *testing.T) {
 		})
 	}
 }
+
+// newLayoutPkg creates package in directory dir of module example.com/m, which imports other directories.
+func newLayoutPkg(name, dir string, imports ...string) *model.Pkg {
+	pkg := model.NewPkg()
+	pkg.Name = name
+	pkg.Path = "/tmp/anticycle/layout/" + dir
+	pkg.ImportPath = "example.com/m/" + dir
+	file := model.NewFile()
+	file.Path = pkg.Path + "/" + name + ".go"
+	for _, imp := range imports {
+		info := &model.ImportInfo{Name: "example.com/m/" + imp, NameShort: imp}
+		pkg.Imports[info.Name] = info
+		fileImp := *info
+		file.Imports = append(file.Imports, &fileImp)
+	}
+	pkg.Files = append(pkg.Files, file)
+	return pkg
+}
+
+func TestAnalyze_WithExternalTestPackage(t *testing.T) {
+	packages, err := FindCycles([]*model.Pkg{
+		newLayoutPkg("foo", "foo"),
+		newLayoutPkg("foo_test", "foo", "foo"),
+	})
+	assert.NoError(t, err)
+
+	analysis := Analyze(packages)
+	assert.Empty(t, analysis.Metadata.Cycles)
+	assert.Empty(t, OnlyAffected(packages))
+}
+
+func TestAnalyze_WithPackageNameOtherThanDirectory(t *testing.T) {
+	packages, err := FindCycles([]*model.Pkg{
+		newLayoutPkg("b", "bdir", "cdir"),
+		newLayoutPkg("c", "cdir", "bdir"),
+	})
+	assert.NoError(t, err)
+
+	analysis := Analyze(OnlyAffected(packages))
+	assert.Equal(t, [][]string{{"b", "c", "b"}, {"c", "b", "c"}}, analysis.Metadata.Cycles)
+}
+
+func TestClonePackages(t *testing.T) {
+	packages, err := FindCycles(newSimulationPackages())
+	assert.NoError(t, err)
+
+	before := ClonePackages(packages)
+	assert.Equal(t, packages, before)
+
+	affected := OnlyAffected(ClonePackages(packages))
+	assert.Len(t, affected, 2)
+	assert.NotEqual(t, before, affected)
+	assert.Equal(t, before, packages)
+}
+
+func TestTimestamp(t *testing.T) {
+	defer os.Unsetenv("SOURCE_DATE_EPOCH")
+
+	os.Setenv("SOURCE_DATE_EPOCH", "1514764800")
+	now, err := Timestamp()
+	assert.NoError(t, err)
+	assert.Equal(t, "2018-01-01T00:00:00Z", now.Format(time.RFC3339))
+
+	os.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	_, err = Timestamp()
+	assert.EqualError(t, err, "SOURCE_DATE_EPOCH='yesterday' is not a number of seconds")
+}
@@ -0,0 +1,62 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Baseline compares cycles of the analysis with cycles of the baseline analysis,
+// e.g. JSON output of previous run. Cycles are compared regardless of package
+// they start from, so each cycle is listed once.
+func Baseline(analysis, baseline *model.Analysis) *model.BaselineReport {
+	report := &model.BaselineReport{
+		New:      make([][]string, 0),
+		Existing: make([][]string, 0),
+		Fixed:    make([][]string, 0),
+	}
+	current := UniqueCycles(analysis.Metadata.Cycles)
+	var previous [][]string
+	if baseline != nil && baseline.Metadata != nil {
+		previous = UniqueCycles(baseline.Metadata.Cycles)
+	}
+
+	previousKeys := make(map[string]bool, len(previous))
+	for _, cycle := range previous {
+		previousKeys[model.CycleKey(cycle)] = true
+	}
+	currentKeys := make(map[string]bool, len(current))
+	for _, cycle := range current {
+		key := model.CycleKey(cycle)
+		currentKeys[key] = true
+		if previousKeys[key] {
+			report.Existing = append(report.Existing, cycle)
+		} else {
+			report.New = append(report.New, cycle)
+		}
+	}
+	for _, cycle := range previous {
+		if !currentKeys[model.CycleKey(cycle)] {
+			report.Fixed = append(report.Fixed, cycle)
+		}
+	}
+	return report
+}
+
+// UniqueCycles removes cycles which differ only by package they start from.
+// The first occurrence of each cycle is kept.
+func UniqueCycles(cycles [][]string) [][]string {
+	unique := make([][]string, 0, len(cycles))
+	seen := make(map[string]bool, len(cycles))
+	for _, cycle := range cycles {
+		key := model.CycleKey(cycle)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, cycle)
+	}
+	return unique
+}
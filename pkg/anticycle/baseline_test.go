@@ -0,0 +1,53 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseline(t *testing.T) {
+	analysis := &model.Analysis{Metadata: &model.AnalysisMeta{Cycles: [][]string{
+		{"bar", "baz", "bar"},
+		{"baz", "bar", "baz"},
+		{"foo", "pas", "foo"},
+	}}}
+	baseline := &model.Analysis{Metadata: &model.AnalysisMeta{Cycles: [][]string{
+		{"baz", "bar", "baz"},
+		{"lib", "util", "lib"},
+	}}}
+
+	report := Baseline(analysis, baseline)
+	assert.Equal(t, [][]string{{"foo", "pas", "foo"}}, report.New)
+	assert.Equal(t, [][]string{{"bar", "baz", "bar"}}, report.Existing)
+	assert.Equal(t, [][]string{{"lib", "util", "lib"}}, report.Fixed)
+}
+
+func TestBaseline_WithEmptyBaseline(t *testing.T) {
+	analysis := &model.Analysis{Metadata: &model.AnalysisMeta{Cycles: [][]string{
+		{"bar", "baz", "bar"},
+	}}}
+
+	report := Baseline(analysis, &model.Analysis{})
+	assert.Equal(t, [][]string{{"bar", "baz", "bar"}}, report.New)
+	assert.Empty(t, report.Existing)
+	assert.Empty(t, report.Fixed)
+}
+
+func TestUniqueCycles(t *testing.T) {
+	cycles := [][]string{
+		{"bar", "foo", "baz", "bar"},
+		{"baz", "bar", "foo", "baz"},
+		{"pas", "bar", "foo", "baz", "bar"},
+		{"foo", "pas", "foo"},
+	}
+	assert.Equal(t, [][]string{
+		{"bar", "foo", "baz", "bar"},
+		{"foo", "pas", "foo"},
+	}, UniqueCycles(cycles))
+}
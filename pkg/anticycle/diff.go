@@ -0,0 +1,164 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/anticycle/anticycle/internal/pkg/scan"
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Diff compares two analyses, e.g. saved JSON reports of nightly runs.
+// Each new cycle absent from the old analysis is paired with the old cycle gone
+// from the new analysis which shares most packages with it and is not paired
+// yet. Paired cycles are changed, unpaired are added or removed, so a new cycle
+// overlapping only unchanged cycles is added. New edges are complete only when both
+// analyses hold all packages, see Collect with all flag. Packages are
+// identified by import path, or by path relative to the root of the analysis,
+// so analyses of the same tree run from different directories are comparable.
+func Diff(oldAnalysis, newAnalysis *model.Analysis) *model.DiffReport {
+	report := &model.DiffReport{
+		Added:         make([][]string, 0),
+		Removed:       make([][]string, 0),
+		Changed:       make([]*model.CycleChange, 0),
+		EnteredCycles: make([]string, 0),
+		LeftCycles:    make([]string, 0),
+		NewEdges:      make([]*model.Edge, 0),
+	}
+
+	oldCycles := UniqueCycles(metaCycles(oldAnalysis))
+	newCycles := UniqueCycles(metaCycles(newAnalysis))
+	oldKeys := make(map[string]bool, len(oldCycles))
+	for _, cycle := range oldCycles {
+		oldKeys[model.CycleKey(cycle)] = true
+	}
+	newKeys := make(map[string]bool, len(newCycles))
+	for _, cycle := range newCycles {
+		newKeys[model.CycleKey(cycle)] = true
+	}
+
+	matched := make(map[string]bool)
+	for _, cycle := range newCycles {
+		key := model.CycleKey(cycle)
+		if oldKeys[key] {
+			continue
+		}
+		var best []string
+		bestShared := 0
+		for _, oldCycle := range oldCycles {
+			oldKey := model.CycleKey(oldCycle)
+			if newKeys[oldKey] || matched[oldKey] {
+				continue
+			}
+			if shared := sharedMembers(key, oldKey); shared > bestShared {
+				best, bestShared = oldCycle, shared
+			}
+		}
+		if best == nil {
+			report.Added = append(report.Added, cycle)
+			continue
+		}
+		matched[model.CycleKey(best)] = true
+		report.Changed = append(report.Changed, &model.CycleChange{Old: best, New: cycle})
+	}
+	for _, cycle := range oldCycles {
+		key := model.CycleKey(cycle)
+		if !newKeys[key] && !matched[key] {
+			report.Removed = append(report.Removed, cycle)
+		}
+	}
+
+	oldInCycle := pkgsInCycle(oldAnalysis)
+	newInCycle := pkgsInCycle(newAnalysis)
+	for pkgPath := range newInCycle {
+		if !oldInCycle[pkgPath] {
+			report.EnteredCycles = append(report.EnteredCycles, pkgPath)
+		}
+	}
+	for pkgPath := range oldInCycle {
+		if !newInCycle[pkgPath] {
+			report.LeftCycles = append(report.LeftCycles, pkgPath)
+		}
+	}
+	sort.Strings(report.EnteredCycles)
+	sort.Strings(report.LeftCycles)
+
+	oldEdges := make(map[model.Edge]bool)
+	for _, edge := range edges(oldAnalysis) {
+		oldEdges[*edge] = true
+	}
+	for _, edge := range edges(newAnalysis) {
+		if !oldEdges[*edge] {
+			report.NewEdges = append(report.NewEdges, edge)
+		}
+	}
+	return report
+}
+
+func metaCycles(analysis *model.Analysis) [][]string {
+	if analysis.Metadata == nil {
+		return [][]string{}
+	}
+	return analysis.Metadata.Cycles
+}
+
+// sharedMembers counts packages present in both cycle keys.
+func sharedMembers(left, right string) int {
+	shared := 0
+	rightMembers := strings.Fields(right)
+	for _, name := range strings.Fields(left) {
+		if model.SliceContains(rightMembers, name) {
+			shared++
+		}
+	}
+	return shared
+}
+
+// pkgKey identifies the package by import path, or by path relative to the root
+// of the analysis, which does not depend on the directory anticycle was run from.
+func pkgKey(analysis *model.Analysis, pkg *model.Pkg) string {
+	if pkg.ImportPath != "" {
+		return pkg.ImportPath
+	}
+	if analysis.Root != "" {
+		if rel, err := filepath.Rel(analysis.Root, pkg.Path); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return pkg.Path
+}
+
+func pkgsInCycle(analysis *model.Analysis) map[string]bool {
+	inCycle := make(map[string]bool)
+	for _, pkg := range analysis.Cycles {
+		if pkg.HaveCycle {
+			inCycle[pkgKey(analysis, pkg)] = true
+		}
+	}
+	return inCycle
+}
+
+// edges lists imports between different packages, sorted by package keys.
+func edges(analysis *model.Analysis) []*model.Edge {
+	packages := analysis.Cycles
+	result := make([]*model.Edge, 0)
+	for from, imports := range scan.Dependencies(packages) {
+		for _, to := range imports {
+			if from != to {
+				result = append(result, &model.Edge{From: pkgKey(analysis, packages[from]), To: pkgKey(analysis, packages[to])})
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].From != result[j].From {
+			return result[i].From < result[j].From
+		}
+		return result[i].To < result[j].To
+	})
+	return result
+}
@@ -0,0 +1,153 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDiffAnalysis(cycles [][]string, packages ...*model.Pkg) *model.Analysis {
+	for _, cycle := range cycles {
+		for _, pkg := range packages {
+			if model.SliceContains(cycle, pkg.Name) {
+				pkg.HaveCycle = true
+			}
+		}
+	}
+	return &model.Analysis{
+		Cycles:   packages,
+		Metadata: &model.AnalysisMeta{Cycles: cycles},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	oldAnalysis := newDiffAnalysis(
+		[][]string{
+			{"bar", "baz", "bar"},
+			{"baz", "bar", "baz"},
+			{"lib", "util", "lib"},
+			{"util", "lib", "util"},
+			{"api", "web", "api"},
+		},
+		newTestPkg("bar", "baz"),
+		newTestPkg("baz", "bar"),
+		newTestPkg("lib", "util"),
+		newTestPkg("util", "lib"),
+		newTestPkg("api", "web"),
+		newTestPkg("web", "api"),
+		newTestPkg("foo"),
+	)
+	newAnalysis := newDiffAnalysis(
+		[][]string{
+			{"api", "web", "api"},
+			{"bar", "foo", "baz", "bar"},
+			{"pas", "run", "pas"},
+		},
+		newTestPkg("bar", "foo"),
+		newTestPkg("baz", "bar"),
+		newTestPkg("foo", "baz"),
+		newTestPkg("lib", "util"),
+		newTestPkg("util"),
+		newTestPkg("api", "web"),
+		newTestPkg("web", "api"),
+		newTestPkg("pas", "run"),
+		newTestPkg("run", "pas"),
+	)
+
+	report := Diff(oldAnalysis, newAnalysis)
+	assert.Equal(t, [][]string{{"pas", "run", "pas"}}, report.Added)
+	assert.Equal(t, [][]string{{"lib", "util", "lib"}}, report.Removed)
+	assert.Equal(t, []*model.CycleChange{{
+		Old: []string{"bar", "baz", "bar"},
+		New: []string{"bar", "foo", "baz", "bar"},
+	}}, report.Changed)
+	assert.Equal(t, []string{
+		"/tmp/anticycle/stability/foo",
+		"/tmp/anticycle/stability/pas",
+		"/tmp/anticycle/stability/run",
+	}, report.EnteredCycles)
+	assert.Equal(t, []string{
+		"/tmp/anticycle/stability/lib",
+		"/tmp/anticycle/stability/util",
+	}, report.LeftCycles)
+	assert.Equal(t, []*model.Edge{
+		{From: "/tmp/anticycle/stability/bar", To: "/tmp/anticycle/stability/foo"},
+		{From: "/tmp/anticycle/stability/foo", To: "/tmp/anticycle/stability/baz"},
+		{From: "/tmp/anticycle/stability/pas", To: "/tmp/anticycle/stability/run"},
+		{From: "/tmp/anticycle/stability/run", To: "/tmp/anticycle/stability/pas"},
+	}, report.NewEdges)
+}
+
+func TestDiff_WithNewCycleOverlappingUnchanged(t *testing.T) {
+	oldAnalysis := newDiffAnalysis(
+		[][]string{{"bar", "baz", "bar"}},
+		newTestPkg("bar", "baz"),
+		newTestPkg("baz", "bar"),
+		newTestPkg("foo"),
+	)
+	newAnalysis := newDiffAnalysis(
+		[][]string{
+			{"bar", "baz", "bar"},
+			{"bar", "foo", "bar"},
+		},
+		newTestPkg("bar", "baz", "foo"),
+		newTestPkg("baz", "bar"),
+		newTestPkg("foo", "bar"),
+	)
+
+	report := Diff(oldAnalysis, newAnalysis)
+	assert.Equal(t, [][]string{{"bar", "foo", "bar"}}, report.Added)
+	assert.Empty(t, report.Removed)
+	assert.Empty(t, report.Changed)
+	assert.Equal(t, []string{"/tmp/anticycle/stability/foo"}, report.EnteredCycles)
+}
+
+func TestDiff_WithSameAnalysis(t *testing.T) {
+	analysis := newDiffAnalysis(
+		[][]string{{"bar", "baz", "bar"}},
+		newTestPkg("bar", "baz"),
+		newTestPkg("baz", "bar"),
+	)
+
+	report := Diff(analysis, analysis)
+	assert.Empty(t, report.Added)
+	assert.Empty(t, report.Removed)
+	assert.Empty(t, report.Changed)
+	assert.Empty(t, report.EnteredCycles)
+	assert.Empty(t, report.LeftCycles)
+	assert.Empty(t, report.NewEdges)
+}
+
+func TestDiff_WithDifferentRoots(t *testing.T) {
+	oldAnalysis := newDiffAnalysis([][]string{}, newTestPkg("bar", "baz"), newTestPkg("baz"))
+	oldAnalysis.Root = "/tmp/anticycle/stability"
+	newBar, newBaz := newTestPkg("bar", "baz"), newTestPkg("baz", "bar")
+	for _, pkg := range []*model.Pkg{newBar, newBaz} {
+		pkg.Path = "stability/" + pkg.Name
+		for _, imp := range pkg.Imports {
+			imp.Name = "stability/" + imp.NameShort
+		}
+	}
+	newAnalysis := newDiffAnalysis([][]string{{"bar", "baz", "bar"}}, newBar, newBaz)
+	newAnalysis.Root = "stability"
+
+	report := Diff(oldAnalysis, newAnalysis)
+	assert.Equal(t, []string{"bar", "baz"}, report.EnteredCycles)
+	assert.Equal(t, []*model.Edge{{From: "baz", To: "bar"}}, report.NewEdges)
+
+	// Import path identifies the package regardless of the root.
+	newBar.ImportPath, newBaz.ImportPath = "example.com/bar", "example.com/baz"
+	report = Diff(oldAnalysis, newAnalysis)
+	assert.Equal(t, []string{"example.com/bar", "example.com/baz"}, report.EnteredCycles)
+}
+
+func TestDiff_WithEmptyAnalyses(t *testing.T) {
+	report := Diff(&model.Analysis{}, &model.Analysis{})
+	assert.Empty(t, report.Added)
+	assert.Empty(t, report.NewEdges)
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"fmt"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Kinds is a list of all import kinds.
+var Kinds = []string{
+	model.KindStdlib,
+	model.KindModule,
+	model.KindLocal,
+	model.KindExternal,
+	model.KindPseudo,
+}
+
+// FilterKinds removes from packages and files all imports of kinds other than given.
+// Should be called before FindCycles, so filtered imports take no part in analysis.
+// Will return error if any of kinds is unknown.
+func FilterKinds(packages []*model.Pkg, kinds []string) ([]*model.Pkg, error) {
+	for _, kind := range kinds {
+		if !model.SliceContains(Kinds, kind) {
+			return packages, fmt.Errorf("import kind '%v' is not available, try one of %v", kind, Kinds)
+		}
+	}
+
+	for _, pkg := range packages {
+		for name, imp := range pkg.Imports {
+			if !model.SliceContains(kinds, imp.Kind) {
+				delete(pkg.Imports, name)
+			}
+		}
+		for _, file := range pkg.Files {
+			imports := make([]*model.ImportInfo, 0, len(file.Imports))
+			for _, imp := range file.Imports {
+				if model.SliceContains(kinds, imp.Kind) {
+					imports = append(imports, imp)
+				}
+			}
+			file.Imports = imports
+		}
+	}
+	return packages, nil
+}
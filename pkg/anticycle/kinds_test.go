@@ -0,0 +1,42 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterKinds(t *testing.T) {
+	fmtImport := &model.ImportInfo{Name: "fmt", NameShort: "fmt", Kind: model.KindStdlib}
+	barImport := &model.ImportInfo{Name: "example.com/m/bar", NameShort: "bar", Kind: model.KindModule}
+	extImport := &model.ImportInfo{Name: "github.com/x/y", NameShort: "y", Kind: model.KindExternal}
+
+	pkg := model.NewPkg()
+	pkg.Name = "foo"
+	pkg.Imports = map[string]*model.ImportInfo{
+		fmtImport.Name: fmtImport,
+		barImport.Name: barImport,
+		extImport.Name: extImport,
+	}
+	pkg.Files = []*model.File{
+		{Path: "foo/foo.go", Imports: []*model.ImportInfo{fmtImport, barImport, extImport}},
+	}
+
+	packages, err := FilterKinds([]*model.Pkg{pkg}, []string{model.KindModule, model.KindExternal})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*model.ImportInfo{
+		barImport.Name: barImport,
+		extImport.Name: extImport,
+	}, packages[0].Imports)
+	assert.Equal(t, []*model.ImportInfo{barImport, extImport}, packages[0].Files[0].Imports)
+}
+
+func TestFilterKinds_WithUnknownKind(t *testing.T) {
+	_, err := FilterKinds([]*model.Pkg{}, []string{"module", "vendor"})
+	assert.EqualError(t, err, "import kind 'vendor' is not available, try one of [stdlib module local external pseudo]")
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"sort"
+
+	"github.com/anticycle/anticycle/internal/pkg/scan"
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Levels collapses packages which import each other into single components
+// and assigns each component a level. Level 0 imports nothing from given packages,
+// level N depends only on components from lower levels.
+// Levels are meaningful only for complete list of packages, see Collect with all flag.
+func Levels(packages []*model.Pkg) *model.Levelization {
+	levelization := &model.Levelization{
+		Layers:       make([]*model.Layer, 0),
+		LongestChain: make([]*model.Component, 0),
+	}
+	deps := scan.Dependencies(packages)
+	sccs := scan.StronglyConnected(deps)
+
+	pkgToComp := make([]int, len(packages))
+	for idx, scc := range sccs {
+		for _, node := range scc {
+			pkgToComp[node] = idx
+		}
+	}
+
+	// Components are in reverse topological order,
+	// so all dependencies have level computed before dependent component.
+	components := make([]*model.Component, len(sccs))
+	levels := make([]int, len(sccs))
+	next := make([]int, len(sccs))
+	for idx, scc := range sccs {
+		next[idx] = -1
+		component := &model.Component{
+			Packages: make([]string, 0, len(scc)),
+			Cycle:    len(scc) > 1,
+		}
+		for _, node := range scc {
+			component.Packages = append(component.Packages, packages[node].Name)
+			for _, dep := range deps[node] {
+				comp := pkgToComp[dep]
+				if comp == idx {
+					continue
+				}
+				// Prefer alphabetically first dependency if levels are equal.
+				if levels[comp]+1 > levels[idx] || (levels[comp]+1 == levels[idx] && less(components[comp], components[next[idx]])) {
+					levels[idx] = levels[comp] + 1
+					next[idx] = comp
+				}
+			}
+		}
+		sort.Strings(component.Packages)
+		components[idx] = component
+
+		for len(levelization.Layers) <= levels[idx] {
+			levelization.Layers = append(levelization.Layers, &model.Layer{
+				Level:      len(levelization.Layers),
+				Components: make([]*model.Component, 0),
+			})
+		}
+		layer := levelization.Layers[levels[idx]]
+		layer.Components = append(layer.Components, component)
+	}
+
+	for _, layer := range levelization.Layers {
+		sort.SliceStable(layer.Components, func(i, j int) bool {
+			return less(layer.Components[i], layer.Components[j])
+		})
+	}
+
+	// Longest chain starts at first component of the highest level
+	// and follows dependencies one level down until level 0.
+	top := -1
+	for idx := range components {
+		if top == -1 || levels[idx] > levels[top] || (levels[idx] == levels[top] && less(components[idx], components[top])) {
+			top = idx
+		}
+	}
+	for comp := top; comp != -1; comp = next[comp] {
+		levelization.LongestChain = append(levelization.LongestChain, components[comp])
+	}
+
+	return levelization
+}
+
+// less compares components by names of theirs packages.
+func less(left, right *model.Component) bool {
+	for i := 0; i < len(left.Packages) && i < len(right.Packages); i++ {
+		if left.Packages[i] != right.Packages[i] {
+			return left.Packages[i] < right.Packages[i]
+		}
+	}
+	return len(left.Packages) < len(right.Packages)
+}
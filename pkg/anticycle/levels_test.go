@@ -0,0 +1,67 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevels(t *testing.T) {
+	packages := []*model.Pkg{
+		newTestPkg("app", "api", "core"),
+		newTestPkg("api", "core"),
+		newTestPkg("core", "helper"),
+		newTestPkg("helper", "conf"),
+		newTestPkg("conf"),
+	}
+	levels := Levels(packages)
+
+	expected := []*model.Layer{
+		{Level: 0, Components: []*model.Component{{Packages: []string{"conf"}}}},
+		{Level: 1, Components: []*model.Component{{Packages: []string{"helper"}}}},
+		{Level: 2, Components: []*model.Component{{Packages: []string{"core"}}}},
+		{Level: 3, Components: []*model.Component{{Packages: []string{"api"}}}},
+		{Level: 4, Components: []*model.Component{{Packages: []string{"app"}}}},
+	}
+	assert.Equal(t, expected, levels.Layers)
+
+	chain := make([]string, 0)
+	for _, component := range levels.LongestChain {
+		chain = append(chain, component.Packages...)
+	}
+	assert.Equal(t, []string{"app", "api", "core", "helper", "conf"}, chain)
+}
+
+func TestLevels_CollapseCycles(t *testing.T) {
+	packages := []*model.Pkg{
+		newTestPkg("bar", "baz"),
+		newTestPkg("baz", "bar", "foo"),
+		newTestPkg("foo"),
+		newTestPkg("pas", "foo"),
+	}
+	levels := Levels(packages)
+
+	expected := []*model.Layer{
+		{Level: 0, Components: []*model.Component{{Packages: []string{"foo"}}}},
+		{Level: 1, Components: []*model.Component{
+			{Packages: []string{"bar", "baz"}, Cycle: true},
+			{Packages: []string{"pas"}},
+		}},
+	}
+	assert.Equal(t, expected, levels.Layers)
+	assert.Equal(t, []*model.Component{
+		{Packages: []string{"bar", "baz"}, Cycle: true},
+		{Packages: []string{"foo"}},
+	}, levels.LongestChain)
+}
+
+func TestLevels_WithEmptyInput(t *testing.T) {
+	levels := Levels([]*model.Pkg{})
+	assert.Empty(t, levels.Layers)
+	assert.Empty(t, levels.LongestChain)
+}
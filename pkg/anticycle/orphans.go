@@ -0,0 +1,86 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/anticycle/anticycle/internal/pkg/scan"
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Orphans lists packages which are not imported by any other analyzed package.
+// Main packages and test packages are never orphans, and imports from test
+// packages are not taken into account. Orphans which match allowed name
+// or path suffix, e.g. public library packages, are listed separately.
+// Orphans are meaningful only for complete list of packages, see Collect with all flag.
+func Orphans(packages []*model.Pkg, allowed []string) *model.OrphanReport {
+	report := &model.OrphanReport{
+		Packages: make([]*model.Orphan, 0),
+		Allowed:  make([]*model.Orphan, 0),
+	}
+
+	imported := make([]bool, len(packages))
+	for from, imports := range scan.Dependencies(packages) {
+		if isTestPkg(packages[from]) {
+			continue
+		}
+		for _, to := range imports {
+			if from != to {
+				imported[to] = true
+			}
+		}
+	}
+
+	for idx, pkg := range packages {
+		if imported[idx] || pkg.Name == "main" || isTestPkg(pkg) {
+			continue
+		}
+		orphan := &model.Orphan{Name: pkg.Name, Path: pkg.Path}
+		if isAllowed(pkg, allowed) {
+			report.Allowed = append(report.Allowed, orphan)
+		} else {
+			report.Packages = append(report.Packages, orphan)
+		}
+	}
+
+	sortOrphans(report.Packages)
+	sortOrphans(report.Allowed)
+	return report
+}
+
+// isTestPkg reports whether package is external test package
+// or consists only of test files.
+func isTestPkg(pkg *model.Pkg) bool {
+	if strings.HasSuffix(pkg.Name, "_test") {
+		return true
+	}
+	for _, file := range pkg.Files {
+		if !strings.HasSuffix(file.Path, "_test.go") {
+			return false
+		}
+	}
+	return len(pkg.Files) > 0
+}
+
+func isAllowed(pkg *model.Pkg, allowed []string) bool {
+	for _, name := range allowed {
+		name = strings.Trim(name, "/")
+		if name == "" {
+			continue
+		}
+		if pkg.Name == name || pkg.Path == name || strings.HasSuffix(pkg.Path, "/"+name) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortOrphans(orphans []*model.Orphan) {
+	sort.SliceStable(orphans, func(i, j int) bool {
+		return orphans[i].Path < orphans[j].Path
+	})
+}
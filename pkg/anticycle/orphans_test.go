@@ -0,0 +1,55 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrphans(t *testing.T) {
+	tests := newTestPkg("tools")
+	tests.Files = []*model.File{{Path: "/tmp/anticycle/stability/tools/tools_test.go"}}
+	packages := []*model.Pkg{
+		newTestPkg("main", "used"),
+		newTestPkg("used"),
+		newTestPkg("lib", "used"),
+		newTestPkg("dead"),
+		newTestPkg("dead_test", "dead"),
+		tests,
+	}
+
+	report := Orphans(packages, []string{})
+	assert.Equal(t, []*model.Orphan{
+		{Name: "dead", Path: "/tmp/anticycle/stability/dead"},
+		{Name: "lib", Path: "/tmp/anticycle/stability/lib"},
+	}, report.Packages)
+	assert.Empty(t, report.Allowed)
+}
+
+func TestOrphans_WithAllowed(t *testing.T) {
+	packages := []*model.Pkg{
+		newTestPkg("lib"),
+		newTestPkg("client"),
+		newTestPkg("dead"),
+	}
+
+	report := Orphans(packages, []string{"lib", "stability/client/", ""})
+	assert.Equal(t, []*model.Orphan{
+		{Name: "dead", Path: "/tmp/anticycle/stability/dead"},
+	}, report.Packages)
+	assert.Equal(t, []*model.Orphan{
+		{Name: "client", Path: "/tmp/anticycle/stability/client"},
+		{Name: "lib", Path: "/tmp/anticycle/stability/lib"},
+	}, report.Allowed)
+}
+
+func TestOrphans_WithEmptyInput(t *testing.T) {
+	report := Orphans([]*model.Pkg{}, []string{})
+	assert.Empty(t, report.Packages)
+	assert.Empty(t, report.Allowed)
+}
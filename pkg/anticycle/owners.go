@@ -0,0 +1,324 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/anticycle/anticycle/internal/pkg/scan"
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// CodeOwnersLocations are paths of CODEOWNERS file, relative to the repository,
+// in order of lookup by GitHub and GitLab.
+var CodeOwnersLocations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+}
+
+// gitlabSection matches header of GitLab section, like "[Backend]", optional
+// "^[Docs]" or "[Database][2]", followed by default owners of the section.
+var gitlabSection = regexp.MustCompile(`^\^?\[([^\]]+)\](?:\[\d+\])?\s*(.*)$`)
+
+// ParseCodeOwners reads rules of CODEOWNERS file in GitHub or GitLab syntax.
+// Rules of GitLab sections without owners get default owners of the section.
+// Will return error if a pattern is not supported or an owner is not
+// a @user, @org/team or an email.
+func ParseCodeOwners(data []byte) (*model.CodeOwners, error) {
+	codeOwners := &model.CodeOwners{Rules: make([]*model.OwnerRule, 0)}
+	section := ""
+	defaults := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if match := gitlabSection.FindStringSubmatch(text); match != nil {
+			section = match[1]
+			defaults = splitOwners(match[2])
+			continue
+		}
+
+		fields := splitOwners(text)
+		rule := &model.OwnerRule{Pattern: fields[0], Owners: fields[1:], Section: section, Line: line}
+		if strings.HasPrefix(rule.Pattern, "!") {
+			return nil, fmt.Errorf("CODEOWNERS line %d: pattern '%v' is not supported, negation does not work in CODEOWNERS", line, rule.Pattern)
+		}
+		if _, err := patternRegexp(rule.Pattern); err != nil {
+			return nil, fmt.Errorf("CODEOWNERS line %d: pattern '%v' is not valid: %v", line, rule.Pattern, err)
+		}
+		if len(rule.Owners) == 0 {
+			rule.Owners = defaults
+		}
+		for _, owner := range rule.Owners {
+			if !strings.Contains(owner, "@") {
+				return nil, fmt.Errorf("CODEOWNERS line %d: owner '%v' is not valid, try @user, @org/team or email", line, owner)
+			}
+		}
+		codeOwners.Rules = append(codeOwners.Rules, rule)
+	}
+	return codeOwners, scanner.Err()
+}
+
+// splitOwners splits the line by white space, which may be escaped with backslash
+// in patterns. Inline comment ends the line.
+func splitOwners(text string) []string {
+	fields := make([]string, 0)
+	var field strings.Builder
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '\\' && i+1 < len(text):
+			field.WriteByte(c)
+			field.WriteByte(text[i+1])
+			i++
+		case c == ' ' || c == '\t':
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		case c == '#' && field.Len() == 0 && len(fields) > 0:
+			return fields
+		default:
+			field.WriteByte(c)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// patternRegexp translates gitignore-like pattern of CODEOWNERS into regular
+// expression which matches slash-separated paths relative to the repository.
+// Pattern without slash matches at any depth, and pattern which matches
+// a directory matches all files in it, except patterns like "docs/*",
+// which match only files directly in the directory.
+func patternRegexp(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if strings.Contains(pattern, "/") {
+		anchored = true
+	}
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		expr.WriteString("/.*$")
+	case strings.HasSuffix(pattern, "/*"):
+		expr.WriteString("$")
+	default:
+		expr.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(expr.String())
+}
+
+// ownerMatcher finds owners of files with compiled rules of CODEOWNERS.
+type ownerMatcher struct {
+	root     string
+	rules    []*model.OwnerRule
+	patterns []*regexp.Regexp
+	owners   map[string][]string
+}
+
+func newOwnerMatcher(codeOwners *model.CodeOwners) *ownerMatcher {
+	root, err := filepath.Abs(codeOwners.Root)
+	if err != nil {
+		root = codeOwners.Root
+	}
+	m := &ownerMatcher{root: root, owners: make(map[string][]string)}
+	for _, rule := range codeOwners.Rules {
+		// Patterns are validated by ParseCodeOwners.
+		if pattern, err := patternRegexp(rule.Pattern); err == nil {
+			m.rules = append(m.rules, rule)
+			m.patterns = append(m.patterns, pattern)
+		}
+	}
+	return m
+}
+
+// ownersOf returns owners of the file, given by path as in packages. The last
+// matching rule wins, and owners of GitLab sections are combined.
+func (m *ownerMatcher) ownersOf(path string) []string {
+	if owners, ok := m.owners[path]; ok {
+		return owners
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	rel, err := filepath.Rel(m.root, abs)
+	owners := make([]string, 0)
+	if err == nil && !strings.HasPrefix(rel, "..") {
+		rel = filepath.ToSlash(rel)
+		sections := make([]string, 0)
+		matched := make(map[string]*model.OwnerRule)
+		for i, rule := range m.rules {
+			if !m.patterns[i].MatchString(rel) {
+				continue
+			}
+			key := strings.ToLower(rule.Section)
+			if _, ok := matched[key]; !ok {
+				sections = append(sections, key)
+			}
+			matched[key] = rule
+		}
+		for _, section := range sections {
+			for _, owner := range matched[section].Owners {
+				if !model.SliceContains(owners, owner) {
+					owners = append(owners, owner)
+				}
+			}
+		}
+	}
+	m.owners[path] = owners
+	return owners
+}
+
+// Owners attributes imports in cycles to owners of affected files, which are set
+// in Cycle.Owners, so it should be called after FindCycles. If teams is true,
+// imports between all packages are aggregated by owners: an import is an edge
+// from every owner of the importing file to every owner of any file
+// of the imported package. Files without owners are skipped.
+func Owners(packages []*model.Pkg, codeOwners *model.CodeOwners, teams bool) *model.OwnersReport {
+	matcher := newOwnerMatcher(codeOwners)
+	report := &model.OwnersReport{
+		File:    codeOwners.File,
+		Unowned: make([]string, 0),
+	}
+	for _, pkg := range packages {
+		for _, cycle := range pkg.Cycles {
+			cycle.Owners = matcher.ownersOf(cycle.AffectedFile)
+			if len(cycle.Owners) == 0 && !model.SliceContains(report.Unowned, cycle.AffectedFile) {
+				report.Unowned = append(report.Unowned, cycle.AffectedFile)
+			}
+		}
+	}
+	sort.Strings(report.Unowned)
+	if teams {
+		report.Teams, report.TeamCycles = teamGraph(packages, matcher)
+	}
+	return report
+}
+
+// teamGraph aggregates imports between packages by owners and finds cycles between owners.
+func teamGraph(packages []*model.Pkg, matcher *ownerMatcher) ([]*model.TeamEdge, [][]string) {
+	names := make([]string, 0)
+	pkgOwners := make([][]string, len(packages))
+	for i, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, owner := range matcher.ownersOf(file.Path) {
+				if !model.SliceContains(pkgOwners[i], owner) {
+					pkgOwners[i] = append(pkgOwners[i], owner)
+				}
+				if !model.SliceContains(names, owner) {
+					names = append(names, owner)
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	ids := make(map[string]int, len(names))
+	for id, name := range names {
+		ids[name] = id
+	}
+
+	idx := scan.NewIndex(packages)
+	imports := make(map[[2]int]int)
+	deps := make([][]int, len(names))
+	for i, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, imp := range file.Imports {
+				j := idx.Find(imp)
+				if j < 0 || j == i {
+					continue
+				}
+				for _, from := range matcher.ownersOf(file.Path) {
+					for _, to := range pkgOwners[j] {
+						if from == to {
+							continue
+						}
+						edge := [2]int{ids[from], ids[to]}
+						if imports[edge] == 0 {
+							deps[edge[0]] = append(deps[edge[0]], edge[1])
+						}
+						imports[edge]++
+					}
+				}
+			}
+		}
+	}
+
+	component := make([]int, len(names))
+	cycles := make([][]string, 0)
+	for c, members := range scan.StronglyConnected(deps) {
+		for _, member := range members {
+			component[member] = c
+		}
+		if len(members) < 2 {
+			continue
+		}
+		// The shortest cycle from the first owner shows how owners depend on each other.
+		cycle := make([]string, 0)
+		for _, step := range shortestChain(deps, members[0], members[0]) {
+			cycle = append(cycle, names[step])
+		}
+		cycles = append(cycles, cycle)
+	}
+	sort.Slice(cycles, func(i, j int) bool {
+		return strings.Join(cycles[i], " ") < strings.Join(cycles[j], " ")
+	})
+
+	edges := make([]*model.TeamEdge, 0, len(imports))
+	for edge, count := range imports {
+		edges = append(edges, &model.TeamEdge{
+			From:    names[edge[0]],
+			To:      names[edge[1]],
+			Imports: count,
+			Cycle:   component[edge[0]] == component[edge[1]],
+		})
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges, cycles
+}
@@ -0,0 +1,139 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCodeOwners(t *testing.T) {
+	data := "# Comment\n" +
+		"*           @org/platform\n" +
+		"\n" +
+		"/docs/      docs@example.com # Inline comment\n" +
+		"my\\ file.go @alice @bob\n"
+
+	codeOwners, err := ParseCodeOwners([]byte(data))
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.OwnerRule{
+		{Pattern: "*", Owners: []string{"@org/platform"}, Line: 2},
+		{Pattern: "/docs/", Owners: []string{"docs@example.com"}, Line: 4},
+		{Pattern: "my\\ file.go", Owners: []string{"@alice", "@bob"}, Line: 5},
+	}, codeOwners.Rules)
+}
+
+func TestParseCodeOwners_WithGitLabSections(t *testing.T) {
+	data := "[Backend] @org/backend\n" +
+		"/api/\n" +
+		"/api/legacy.go @alice\n" +
+		"^[Database][2] @dba\n" +
+		"*.sql\n"
+
+	codeOwners, err := ParseCodeOwners([]byte(data))
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.OwnerRule{
+		{Pattern: "/api/", Owners: []string{"@org/backend"}, Section: "Backend", Line: 2},
+		{Pattern: "/api/legacy.go", Owners: []string{"@alice"}, Section: "Backend", Line: 3},
+		{Pattern: "*.sql", Owners: []string{"@dba"}, Section: "Database", Line: 5},
+	}, codeOwners.Rules)
+}
+
+func TestParseCodeOwners_WithWrongRules(t *testing.T) {
+	tests := []struct {
+		data, expected string
+	}{
+		{
+			data:     "* @org/platform\n!/api/ @org/web\n",
+			expected: "CODEOWNERS line 2: pattern '!/api/' is not supported, negation does not work in CODEOWNERS",
+		},
+		{
+			data:     "/api/ web-team\n",
+			expected: "CODEOWNERS line 1: owner 'web-team' is not valid, try @user, @org/team or email",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := ParseCodeOwners([]byte(test.data))
+		assert.EqualError(t, err, test.expected)
+	}
+}
+
+func TestPatternRegexp(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		matches   []string
+		unmatched []string
+	}{
+		{pattern: "*", matches: []string{"main.go", "api/api.go"}},
+		{pattern: "*.go", matches: []string{"main.go", "api/api.go"}, unmatched: []string{"README.md"}},
+		{pattern: "/api/", matches: []string{"api/api.go", "api/v1/api.go"}, unmatched: []string{"app/api/api.go", "api"}},
+		{pattern: "api/", matches: []string{"api/api.go", "app/api/api.go"}, unmatched: []string{"api", "apis/api.go"}},
+		{pattern: "api", matches: []string{"api", "api/api.go", "app/api/api.go"}, unmatched: []string{"apis/api.go"}},
+		{pattern: "/api/*", matches: []string{"api/api.go"}, unmatched: []string{"api/v1/api.go"}},
+		{pattern: "**/store", matches: []string{"store/store.go", "app/store/store.go"}},
+		{pattern: "/app/**/*.sql", matches: []string{"app/init.sql", "app/db/init.sql"}, unmatched: []string{"init.sql"}},
+		{pattern: "/api/v?.go", matches: []string{"api/v1.go"}, unmatched: []string{"api/v10.go"}},
+		{pattern: "my\\ file.go", matches: []string{"my file.go"}},
+	}
+
+	for _, test := range tests {
+		expr, err := patternRegexp(test.pattern)
+		assert.NoError(t, err)
+		for _, path := range test.matches {
+			assert.True(t, expr.MatchString(path), "%v should match %v", test.pattern, path)
+		}
+		for _, path := range test.unmatched {
+			assert.False(t, expr.MatchString(path), "%v should not match %v", test.pattern, path)
+		}
+	}
+}
+
+func TestOwners(t *testing.T) {
+	packages, err := FindCycles(newSimulationPackages())
+	assert.NoError(t, err)
+	codeOwners, err := ParseCodeOwners([]byte("* @org/platform\n/store/ @org/data\n/store/store.go\n"))
+	assert.NoError(t, err)
+	codeOwners.File = simulationDir + "/CODEOWNERS"
+	codeOwners.Root = simulationDir
+
+	report := Owners(packages, codeOwners, false)
+	assert.Equal(t, simulationDir+"/CODEOWNERS", report.File)
+	assert.Equal(t, []string{simulationDir + "/store/store.go"}, report.Unowned)
+	assert.Nil(t, report.Teams)
+	assert.Equal(t, []string{"@org/platform"}, packages[0].Cycles[0].Owners)
+	assert.Empty(t, packages[1].Cycles[0].Owners)
+}
+
+func TestOwners_WithGitLabSections(t *testing.T) {
+	packages, err := FindCycles(newSimulationPackages())
+	assert.NoError(t, err)
+	codeOwners, err := ParseCodeOwners([]byte("* @org/platform\n/api/ @org/web\n[Review]\napi/ @alice\n"))
+	assert.NoError(t, err)
+	codeOwners.Root = simulationDir
+
+	report := Owners(packages, codeOwners, false)
+	assert.Equal(t, []string{"@org/web", "@alice"}, packages[0].Cycles[0].Owners)
+	assert.Equal(t, []string{"@org/platform"}, packages[1].Cycles[0].Owners)
+	assert.Empty(t, report.Unowned)
+}
+
+func TestOwners_WithTeams(t *testing.T) {
+	packages, err := FindCycles(newSimulationPackages())
+	assert.NoError(t, err)
+	codeOwners, err := ParseCodeOwners([]byte("* @org/platform\n/store/ @org/data\n/store/store.go @org/core\n"))
+	assert.NoError(t, err)
+	codeOwners.Root = simulationDir
+
+	report := Owners(packages, codeOwners, true)
+	assert.Equal(t, []*model.TeamEdge{
+		{From: "@org/core", To: "@org/platform", Imports: 1, Cycle: true},
+		{From: "@org/platform", To: "@org/core", Imports: 1, Cycle: true},
+		{From: "@org/platform", To: "@org/data", Imports: 1, Cycle: false},
+	}, report.Teams)
+	assert.Equal(t, [][]string{{"@org/core", "@org/platform", "@org/core"}}, report.TeamCycles)
+}
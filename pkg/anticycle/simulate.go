@@ -0,0 +1,166 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/anticycle/anticycle/internal/pkg/scan"
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// ParseEdit reads edit written as operation followed by a file and a target, e.g.
+// "move models/errors.go errs", "remove api/api.go app/store" or "add store/store.go app/api".
+func ParseEdit(text string) (*model.Edit, error) {
+	fields := strings.Fields(text)
+	if len(fields) != 3 || !model.SliceContains([]string{model.EditMove, model.EditRemove, model.EditAdd}, fields[0]) {
+		return nil, fmt.Errorf("edit '%v' is not valid, try 'move FILE DIR', 'remove FILE IMPORT' or 'add FILE IMPORT'", text)
+	}
+	return &model.Edit{Op: fields[0], File: fields[1], Target: fields[2]}, nil
+}
+
+// ApplyEdits changes packages as if the source code was edited. Files are given
+// by path, relative to the analyzed directory or as in the packages, and the target
+// directory of move is relative to the analyzed directory. A moved file keeps its
+// imports and packages which import it are not changed, add imports to simulate
+// such changes. Should be called before FindCycles, like FilterKinds.
+// Will return error if a file is not found or does not have removed import.
+func ApplyEdits(packages []*model.Pkg, dir string, edits []*model.Edit) ([]*model.Pkg, error) {
+	for _, edit := range edits {
+		pkg, file := findFile(packages, dir, edit.File)
+		if file == nil {
+			return packages, fmt.Errorf("file '%v' is not found", edit.File)
+		}
+
+		switch edit.Op {
+		case model.EditMove:
+			packages = moveFile(packages, dir, pkg, file, edit.Target)
+		case model.EditRemove:
+			imports := make([]*model.ImportInfo, 0, len(file.Imports))
+			for _, imp := range file.Imports {
+				if imp.Name != edit.Target {
+					imports = append(imports, imp)
+				}
+			}
+			if len(imports) == len(file.Imports) {
+				return packages, fmt.Errorf("file '%v' does not import '%v'", edit.File, edit.Target)
+			}
+			file.Imports = imports
+			resetImports(pkg)
+		case model.EditAdd:
+			file.Imports = append(file.Imports, &model.ImportInfo{Name: edit.Target, NameShort: path.Base(edit.Target)})
+			resetImports(pkg)
+		default:
+			return packages, fmt.Errorf("edit '%v' is not available, try one of '%v', '%v', '%v'",
+				edit.Op, model.EditMove, model.EditRemove, model.EditAdd)
+		}
+	}
+	// Moved and added imports may point to other packages now.
+	scan.ClassifyImports(packages)
+	return packages, nil
+}
+
+func findFile(packages []*model.Pkg, dir, name string) (*model.Pkg, *model.File) {
+	name = filepath.Clean(name)
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			if file.Path == name || file.Path == filepath.Join(dir, name) {
+				return pkg, file
+			}
+		}
+	}
+	return nil, nil
+}
+
+// moveFile moves file with its suppressions to package in target directory,
+// which is created if needed. Package left without files is removed.
+func moveFile(packages []*model.Pkg, dir string, from *model.Pkg, file *model.File, target string) []*model.Pkg {
+	target = filepath.Join(dir, target)
+	var to *model.Pkg
+	for _, pkg := range packages {
+		if pkg.Path == target {
+			to = pkg
+			break
+		}
+	}
+	if to == nil {
+		to = model.NewPkg()
+		to.Name = filepath.Base(target)
+		to.Path = target
+		to.Module = from.Module
+		if rel, err := filepath.Rel(from.Path, target); err == nil && from.ImportPath != "" {
+			to.ImportPath = path.Join(from.ImportPath, filepath.ToSlash(rel))
+		}
+		packages = append(packages, to)
+	}
+	if to == from {
+		return packages
+	}
+
+	files := make([]*model.File, 0, len(from.Files))
+	for _, f := range from.Files {
+		if f != file {
+			files = append(files, f)
+		}
+	}
+	from.Files = files
+	to.Files = append(to.Files, file)
+
+	suppressions := make([]*model.Suppression, 0, len(from.Suppressions))
+	for _, suppression := range from.Suppressions {
+		if suppression.File == file.Path {
+			suppression.Pkg = to.Name
+			to.Suppressions = append(to.Suppressions, suppression)
+		} else {
+			suppressions = append(suppressions, suppression)
+		}
+	}
+	from.Suppressions = suppressions
+	resetImports(from)
+	resetImports(to)
+
+	if len(from.Files) == 0 {
+		remaining := make([]*model.Pkg, 0, len(packages)-1)
+		for _, pkg := range packages {
+			if pkg != from {
+				remaining = append(remaining, pkg)
+			}
+		}
+		packages = remaining
+	}
+	return packages
+}
+
+// resetImports builds imports of the package from imports of its files.
+func resetImports(pkg *model.Pkg) {
+	pkg.Imports = make(map[string]*model.ImportInfo)
+	for _, file := range pkg.Files {
+		for _, imp := range file.Imports {
+			// Package import is suppressed only if all files suppress it.
+			if pkgImport, ok := pkg.Imports[imp.Name]; ok {
+				pkgImport.Suppressed = pkgImport.Suppressed && imp.Suppressed
+				continue
+			}
+			pkgImport := *imp
+			pkgImport.Line = 0
+			pkg.Imports[imp.Name] = &pkgImport
+		}
+	}
+}
+
+// Simulation compares cycles of analyses before and after edits.
+func Simulation(before, after *model.Analysis, edits []*model.Edit) *model.SimulationReport {
+	baseline := Baseline(after, before)
+	return &model.SimulationReport{
+		Edits:      edits,
+		Before:     UniqueCycles(before.Metadata.Cycles),
+		After:      UniqueCycles(after.Metadata.Cycles),
+		Resolved:   baseline.Fixed,
+		Introduced: baseline.New,
+	}
+}
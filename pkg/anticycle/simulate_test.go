@@ -0,0 +1,141 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const simulationDir = "/tmp/anticycle/stability"
+
+// newSimulationPackages creates api and store packages importing each other,
+// store has additional errors.go file without imports.
+func newSimulationPackages() []*model.Pkg {
+	api := newTestPkg("api", "store")
+	api.Files = []*model.File{
+		{Path: simulationDir + "/api/api.go", Imports: []*model.ImportInfo{
+			{Name: simulationDir + "/store", NameShort: "store", Line: 3},
+		}},
+	}
+	store := newTestPkg("store", "api")
+	store.Files = []*model.File{
+		{Path: simulationDir + "/store/errors.go", Imports: []*model.ImportInfo{}},
+		{Path: simulationDir + "/store/store.go", Imports: []*model.ImportInfo{
+			{Name: simulationDir + "/api", NameShort: "api", Line: 3},
+		}},
+	}
+	return []*model.Pkg{api, store}
+}
+
+func simulationCycles(t *testing.T, packages []*model.Pkg) *model.Analysis {
+	packages, err := FindCycles(packages)
+	assert.NoError(t, err)
+	return Analyze(OnlyAffected(packages))
+}
+
+func TestParseEdit(t *testing.T) {
+	edit, err := ParseEdit("  move models/errors.go   errs ")
+	assert.NoError(t, err)
+	assert.Equal(t, &model.Edit{Op: model.EditMove, File: "models/errors.go", Target: "errs"}, edit)
+
+	for _, text := range []string{"", "move models/errors.go", "rename a.go b.go", "add a.go b c"} {
+		_, err := ParseEdit(text)
+		assert.EqualError(t, err, "edit '"+text+"' is not valid, try 'move FILE DIR', 'remove FILE IMPORT' or 'add FILE IMPORT'")
+	}
+}
+
+func TestApplyEdits_Remove(t *testing.T) {
+	packages, err := ApplyEdits(newSimulationPackages(), simulationDir, []*model.Edit{
+		{Op: model.EditRemove, File: "store/store.go", Target: simulationDir + "/api"},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, packages[1].Files[1].Imports)
+	assert.Empty(t, packages[1].Imports)
+	assert.Empty(t, simulationCycles(t, packages).Metadata.Cycles)
+}
+
+func TestApplyEdits_Add(t *testing.T) {
+	packages, err := ApplyEdits(newSimulationPackages(), simulationDir, []*model.Edit{
+		{Op: model.EditRemove, File: simulationDir + "/store/store.go", Target: simulationDir + "/api"},
+		{Op: model.EditAdd, File: "store/errors.go", Target: simulationDir + "/api"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "api", packages[1].Imports[simulationDir+"/api"].NameShort)
+	assert.Equal(t, [][]string{{"api", "store", "api"}, {"store", "api", "store"}},
+		simulationCycles(t, packages).Metadata.Cycles)
+}
+
+func TestApplyEdits_Move(t *testing.T) {
+	packages := newSimulationPackages()
+	packages[1].Suppressions = []*model.Suppression{
+		{Pkg: "store", File: simulationDir + "/store/store.go", Line: 2, Directive: model.DirectiveAllowCycle, Import: simulationDir + "/api"},
+	}
+
+	packages, err := ApplyEdits(packages, simulationDir, []*model.Edit{
+		{Op: model.EditMove, File: "store/store.go", Target: "repo"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, packages, 3)
+	store, repo := packages[1], packages[2]
+	assert.Len(t, store.Files, 1)
+	assert.Empty(t, store.Imports)
+	assert.Empty(t, store.Suppressions)
+	assert.Equal(t, "repo", repo.Name)
+	assert.Equal(t, simulationDir+"/repo", repo.Path)
+	assert.Contains(t, repo.Imports, simulationDir+"/api")
+	assert.Equal(t, "repo", repo.Suppressions[0].Pkg)
+	assert.Empty(t, simulationCycles(t, packages).Metadata.Cycles)
+
+	// Package left without files is removed.
+	packages, err = ApplyEdits(packages, simulationDir, []*model.Edit{
+		{Op: model.EditMove, File: "store/errors.go", Target: "repo"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, packages, 2)
+	assert.Len(t, repo.Files, 2)
+}
+
+func TestApplyEdits_WithWrongEdits(t *testing.T) {
+	tests := []struct {
+		edit     *model.Edit
+		expected string
+	}{
+		{
+			edit:     &model.Edit{Op: model.EditMove, File: "store/db.go", Target: "repo"},
+			expected: "file 'store/db.go' is not found",
+		},
+		{
+			edit:     &model.Edit{Op: model.EditRemove, File: "store/errors.go", Target: "fmt"},
+			expected: "file 'store/errors.go' does not import 'fmt'",
+		},
+		{
+			edit:     &model.Edit{Op: "rename", File: "store/errors.go", Target: "errs.go"},
+			expected: "edit 'rename' is not available, try one of 'move', 'remove', 'add'",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := ApplyEdits(newSimulationPackages(), simulationDir, []*model.Edit{test.edit})
+		assert.EqualError(t, err, test.expected)
+	}
+}
+
+func TestSimulation(t *testing.T) {
+	edits := []*model.Edit{{Op: model.EditRemove, File: "api/api.go", Target: simulationDir + "/store"}}
+	before := simulationCycles(t, newSimulationPackages())
+	packages, err := ApplyEdits(newSimulationPackages(), simulationDir, edits)
+	assert.NoError(t, err)
+	after := simulationCycles(t, packages)
+
+	report := Simulation(before, after, edits)
+	assert.Equal(t, edits, report.Edits)
+	assert.Equal(t, [][]string{{"api", "store", "api"}}, report.Before)
+	assert.Empty(t, report.After)
+	assert.Equal(t, [][]string{{"api", "store", "api"}}, report.Resolved)
+	assert.Empty(t, report.Introduced)
+}
@@ -0,0 +1,317 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/anticycle/anticycle/internal/pkg/scan"
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Split proposes how files of the package, given by name or path, could be moved
+// to a new package to break cycles of the package. Files of the package are parsed
+// fully to find which of them use identifiers declared in others. For every package
+// imported in a cycle, files which import it are grouped with files which use them,
+// so remaining files do not depend on the group and do not import the new package.
+//
+// Every proposal is checked by edits like in ApplyEdits, where moved files import
+// the package if they use its remaining files, and files of other packages which use
+// identifiers declared in moved files import the new package. Identifiers are matched
+// by name, without type checking, so the proposal is a starting point of a refactoring.
+// Cycles are the shortest cycles through every import of the package.
+// Given packages are not modified, they should be prepared like for FindCycles.
+func Split(packages []*model.Pkg, dir, name string) (*model.SplitPlan, error) {
+	i, err := findPkg(packages, name)
+	if err != nil {
+		return nil, err
+	}
+	pkg := packages[i]
+	plan := &model.SplitPlan{
+		Pkg:       pkg.Name,
+		Path:      pkg.Path,
+		Cycles:    make([][]string, 0),
+		Files:     make([]*model.FileDeps, 0, len(pkg.Files)),
+		Proposals: make([]*model.SplitProposal, 0),
+	}
+
+	decls := make([]*scan.FileDecls, 0, len(pkg.Files))
+	for _, file := range pkg.Files {
+		fileDecls, err := scan.ParseDecls(file.Path)
+		if err != nil {
+			return nil, err
+		}
+		decls = append(decls, fileDecls)
+	}
+	deps := fileDependencies(decls)
+	for f, file := range pkg.Files {
+		fileDeps := &model.FileDeps{File: relPath(dir, file.Path), DependsOn: make([]string, 0, len(deps[f]))}
+		for _, dep := range deps[f] {
+			fileDeps.DependsOn = append(fileDeps.DependsOn, relPath(dir, pkg.Files[dep].Path))
+		}
+		plan.Files = append(plan.Files, fileDeps)
+	}
+
+	cycles := cyclesThrough(packages, i)
+	if len(cycles) == 0 {
+		return plan, nil
+	}
+	usages, err := importerUsages(packages, i)
+	if err != nil {
+		return nil, err
+	}
+	// Moved files import the package the same way as other packages do.
+	pkgImport := importPath(pkg)
+	for _, usage := range usages {
+		for name := range usage.Selected {
+			pkgImport = name
+		}
+	}
+	idx := scan.NewIndex(packages)
+	for _, cycle := range cycles {
+		plan.Cycles = append(plan.Cycles, cycle.names)
+		proposal := splitProposal(dir, packages, i, cycle.next, idx, pkgImport, decls, deps, usages)
+		if !proposal.Whole {
+			if err := checkProposal(packages, dir, pkg, cycles, proposal); err != nil {
+				return nil, err
+			}
+		}
+		plan.Proposals = append(plan.Proposals, proposal)
+	}
+	return plan, nil
+}
+
+// pkgCycle is a cycle of the package which starts with import of package at index next.
+type pkgCycle struct {
+	next  int
+	names []string
+}
+
+// cyclesThrough returns the shortest cycle through every package imported
+// by the package at index i, which depends back on the package.
+func cyclesThrough(packages []*model.Pkg, i int) []*pkgCycle {
+	deps := scan.CycleDependencies(packages)
+	cycles := make([]*pkgCycle, 0)
+	for _, next := range deps[i] {
+		// Import of the package itself is not a cycle through other packages.
+		if next == i {
+			continue
+		}
+		steps := shortestChain(deps, next, i)
+		if steps == nil {
+			continue
+		}
+		names := []string{packages[i].Name}
+		for _, step := range steps {
+			names = append(names, packages[step].Name)
+		}
+		cycles = append(cycles, &pkgCycle{next: next, names: names})
+	}
+	return cycles
+}
+
+// checkProposal applies edits of the proposal to copies of packages. Cycles of the package
+// which do not start with the same import anymore are resolved, cycles through
+// the new package are introduced.
+func checkProposal(packages []*model.Pkg, dir string, pkg *model.Pkg, cycles []*pkgCycle, proposal *model.SplitProposal) error {
+	after, err := ApplyEdits(ClonePackages(packages), dir, proposal.Edits)
+	if err != nil {
+		return err
+	}
+	newPath := filepath.Join(dir, proposal.Target)
+	for j, afterPkg := range after {
+		switch afterPkg.Path {
+		case pkg.Path:
+			remaining := cyclesThrough(after, j)
+			for _, cycle := range cycles {
+				if !cycleRemains(cycle, remaining, packages, after) {
+					proposal.Resolved = append(proposal.Resolved, cycle.names)
+				}
+			}
+		case newPath:
+			for _, cycle := range cyclesThrough(after, j) {
+				proposal.Introduced = append(proposal.Introduced, cycle.names)
+			}
+		}
+	}
+	return nil
+}
+
+// cycleRemains checks if any of remaining cycles starts with import of the same package.
+func cycleRemains(cycle *pkgCycle, remaining []*pkgCycle, before, after []*model.Pkg) bool {
+	for _, afterCycle := range remaining {
+		if after[afterCycle.next].Path == before[cycle.next].Path {
+			return true
+		}
+	}
+	return false
+}
+
+// splitProposal moves files which import the package at index next, and files which
+// depend on them, to a new package named after both packages, next to the split package.
+func splitProposal(dir string, packages []*model.Pkg, i, next int, idx *scan.Index, pkgImport string,
+	decls []*scan.FileDecls, deps [][]int, usages []*scan.FileDecls) *model.SplitProposal {
+
+	pkg, cycled := packages[i], packages[next]
+	var importName string
+	seeds := make([]int, 0)
+	for f, file := range pkg.Files {
+		for _, imp := range file.Imports {
+			if idx.Find(imp) == next {
+				if importName == "" {
+					importName = imp.Name
+				}
+				seeds = append(seeds, f)
+				break
+			}
+		}
+	}
+
+	moved := make(map[int]bool, len(pkg.Files))
+	for _, f := range seeds {
+		moved[f] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for f := range pkg.Files {
+			if moved[f] {
+				continue
+			}
+			for _, dep := range deps[f] {
+				if moved[dep] {
+					moved[f] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	target := filepath.Join(filepath.Dir(relPath(dir, pkg.Path)), pkg.Name+cycled.Name)
+	proposal := &model.SplitProposal{
+		Import:     importName,
+		Target:     target,
+		Files:      make([]string, 0, len(moved)),
+		Whole:      len(moved) == len(pkg.Files),
+		Edits:      make([]*model.Edit, 0),
+		Resolved:   make([][]string, 0),
+		Introduced: make([][]string, 0),
+	}
+	for f, file := range pkg.Files {
+		if moved[f] {
+			proposal.Files = append(proposal.Files, relPath(dir, file.Path))
+		}
+	}
+	if proposal.Whole {
+		return proposal
+	}
+
+	for _, file := range proposal.Files {
+		proposal.Edits = append(proposal.Edits, &model.Edit{Op: model.EditMove, File: file, Target: target})
+	}
+	for f, file := range pkg.Files {
+		if !moved[f] {
+			continue
+		}
+		for _, dep := range deps[f] {
+			if !moved[dep] {
+				proposal.Edits = append(proposal.Edits, &model.Edit{Op: model.EditAdd, File: relPath(dir, file.Path), Target: pkgImport})
+				break
+			}
+		}
+	}
+
+	movedNames := make(map[string]bool)
+	for f := range pkg.Files {
+		if !moved[f] {
+			continue
+		}
+		for _, name := range decls[f].Declared {
+			movedNames[name] = true
+		}
+	}
+	newImport := path.Join(path.Dir(pkgImport), filepath.Base(target))
+	for _, usage := range usages {
+		usesMoved := false
+		for _, names := range usage.Selected {
+			for _, name := range names {
+				usesMoved = usesMoved || movedNames[name]
+			}
+		}
+		if usesMoved {
+			proposal.Edits = append(proposal.Edits, &model.Edit{Op: model.EditAdd, File: relPath(dir, usage.Path), Target: newImport})
+		}
+	}
+	return proposal
+}
+
+// fileDependencies builds adjacency list of files which use identifiers declared in other files.
+func fileDependencies(decls []*scan.FileDecls) [][]int {
+	declaredIn := make(map[string]int)
+	for f, fileDecls := range decls {
+		for _, name := range fileDecls.Declared {
+			declaredIn[name] = f
+		}
+	}
+	deps := make([][]int, len(decls))
+	for f, fileDecls := range decls {
+		deps[f] = make([]int, 0)
+		seen := make(map[int]bool)
+		for _, name := range fileDecls.Referenced {
+			if dep, ok := declaredIn[name]; ok && dep != f && !seen[dep] {
+				seen[dep] = true
+				deps[f] = append(deps[f], dep)
+			}
+		}
+		sort.Ints(deps[f])
+	}
+	return deps
+}
+
+// importerUsages parses files of other packages which import the package at index i.
+// Selected identifiers of each file are limited to the package.
+func importerUsages(packages []*model.Pkg, i int) ([]*scan.FileDecls, error) {
+	idx := scan.NewIndex(packages)
+	usages := make([]*scan.FileDecls, 0)
+	for j, pkg := range packages {
+		if j == i {
+			continue
+		}
+		for _, file := range pkg.Files {
+			for _, imp := range file.Imports {
+				if idx.Find(imp) != i {
+					continue
+				}
+				fileDecls, err := scan.ParseDecls(file.Path)
+				if err != nil {
+					return nil, err
+				}
+				fileDecls.Selected = map[string][]string{imp.Name: fileDecls.Selected[imp.Name]}
+				usages = append(usages, fileDecls)
+				break
+			}
+		}
+	}
+	return usages, nil
+}
+
+// importPath returns import path which refers to the package.
+func importPath(pkg *model.Pkg) string {
+	if pkg.ImportPath != "" {
+		return pkg.ImportPath
+	}
+	return filepath.ToSlash(pkg.Path)
+}
+
+// relPath returns path relative to the directory, or the path if it is outside of the directory.
+func relPath(dir, p string) string {
+	rel, err := filepath.Rel(dir, p)
+	if err != nil {
+		return p
+	}
+	return rel
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// makeSplitProject creates models package which is in cycles with api and db.
+// The db package uses Handle, declared in a file which depends on import of api.
+func makeSplitProject(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "anticycle-split")
+	assert.NoError(t, err)
+	files := map[string]string{
+		"models/model.go":   "package models\ntype User struct{ Name string }",
+		"models/render.go":  "package models\nimport \"DIR/api\"\nfunc Render(u User) string { return api.Format(u.Name) }",
+		"models/handler.go": "package models\nfunc Handle() string { return Render(User{}) }",
+		"models/store.go":   "package models\nimport \"DIR/db\"\nfunc Store(u User) { db.Save(u.Name) }",
+		"api/api.go":        "package api\nimport \"DIR/models\"\nfunc Format(s string) string { return s }\nvar Empty = models.User{}",
+		"db/db.go":          "package db\nimport \"DIR/models\"\nfunc Save(s string) { models.Handle() }",
+	}
+	for name, data := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0700))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(strings.Replace(data, "DIR", dir, -1)), 0600))
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestSplit(t *testing.T) {
+	dir, remove := makeSplitProject(t)
+	defer remove()
+	packages, err := Fetch(dir, []string{})
+	assert.NoError(t, err)
+
+	plan, err := Split(packages, dir, "models")
+	assert.NoError(t, err)
+	assert.Equal(t, "models", plan.Pkg)
+	assert.Equal(t, filepath.Join(dir, "models"), plan.Path)
+	assert.Equal(t, [][]string{{"models", "api", "models"}, {"models", "db", "models"}}, plan.Cycles)
+	assert.Equal(t, []*model.FileDeps{
+		{File: "models/handler.go", DependsOn: []string{"models/model.go", "models/render.go"}},
+		{File: "models/model.go", DependsOn: []string{}},
+		{File: "models/render.go", DependsOn: []string{"models/model.go"}},
+		{File: "models/store.go", DependsOn: []string{"models/model.go"}},
+	}, plan.Files)
+	assert.Len(t, plan.Proposals, 2)
+
+	api := plan.Proposals[0]
+	assert.Equal(t, dir+"/api", api.Import)
+	assert.Equal(t, "modelsapi", api.Target)
+	assert.Equal(t, []string{"models/handler.go", "models/render.go"}, api.Files)
+	assert.False(t, api.Whole)
+	assert.Equal(t, []*model.Edit{
+		{Op: model.EditMove, File: "models/handler.go", Target: "modelsapi"},
+		{Op: model.EditMove, File: "models/render.go", Target: "modelsapi"},
+		{Op: model.EditAdd, File: "models/handler.go", Target: dir + "/models"},
+		{Op: model.EditAdd, File: "models/render.go", Target: dir + "/models"},
+		{Op: model.EditAdd, File: "db/db.go", Target: dir + "/modelsapi"},
+	}, api.Edits)
+	assert.Equal(t, [][]string{{"models", "api", "models"}}, api.Resolved)
+	assert.Equal(t, [][]string{
+		{"modelsapi", "api", "models", "db", "modelsapi"},
+		{"modelsapi", "models", "db", "modelsapi"},
+	}, api.Introduced)
+
+	db := plan.Proposals[1]
+	assert.Equal(t, []string{"models/store.go"}, db.Files)
+	assert.Equal(t, [][]string{{"models", "db", "models"}}, db.Resolved)
+	assert.Empty(t, db.Introduced)
+
+	// Proposals are checked on copies of packages.
+	assert.Len(t, packages, 3)
+	assert.Len(t, packages[2].Files, 4)
+}
+
+func TestSplit_WholePackage(t *testing.T) {
+	dir, remove := makeSplitProject(t)
+	defer remove()
+	packages, err := Fetch(dir, []string{})
+	assert.NoError(t, err)
+
+	plan, err := Split(packages, dir, "db")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"db", "models", "db"}}, plan.Cycles)
+	assert.Len(t, plan.Proposals, 1)
+	assert.True(t, plan.Proposals[0].Whole)
+	assert.Equal(t, []string{"db/db.go"}, plan.Proposals[0].Files)
+	assert.Empty(t, plan.Proposals[0].Edits)
+}
+
+func TestSplit_WithoutCycles(t *testing.T) {
+	packages := []*model.Pkg{
+		newTestPkg("app", "core"),
+		newTestPkg("core"),
+	}
+
+	plan, err := Split(packages, simulationDir, "core")
+	assert.NoError(t, err)
+	assert.Empty(t, plan.Cycles)
+	assert.Empty(t, plan.Proposals)
+
+	_, err = Split(packages, simulationDir, "api")
+	assert.EqualError(t, err, "package 'api' is not found")
+}
@@ -0,0 +1,77 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"sort"
+
+	"github.com/anticycle/anticycle/internal/pkg/scan"
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Stability computes coupling metrics of each package and lists all imports
+// which violate the Stable Dependencies Principle, it is where package depends
+// on package less stable than itself. Violations are sorted by severity.
+// Metrics are meaningful only for complete list of packages, see Collect with all flag.
+func Stability(packages []*model.Pkg) *model.StabilityReport {
+	report := &model.StabilityReport{
+		Packages:   make([]*model.Stability, 0, len(packages)),
+		Violations: make([]*model.SDPViolation, 0),
+	}
+	deps := scan.Dependencies(packages)
+
+	// Self imports do not couple package to any other package.
+	afferent := make([]int, len(packages))
+	efferent := make([]int, len(packages))
+	for from, imports := range deps {
+		for _, to := range imports {
+			if from != to {
+				afferent[to]++
+				efferent[from]++
+			}
+		}
+	}
+
+	for idx, pkg := range packages {
+		stability := &model.Stability{
+			Name:     pkg.Name,
+			Path:     pkg.Path,
+			Afferent: afferent[idx],
+			Efferent: efferent[idx],
+		}
+		if total := stability.Afferent + stability.Efferent; total > 0 {
+			stability.Instability = float64(stability.Efferent) / float64(total)
+		}
+		report.Packages = append(report.Packages, stability)
+	}
+
+	for from, imports := range deps {
+		for _, to := range imports {
+			delta := report.Packages[to].Instability - report.Packages[from].Instability
+			if delta > 0 {
+				report.Violations = append(report.Violations, &model.SDPViolation{
+					From:  report.Packages[from],
+					To:    report.Packages[to],
+					Delta: delta,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(report.Packages, func(i, j int) bool {
+		return report.Packages[i].Path < report.Packages[j].Path
+	})
+	sort.SliceStable(report.Violations, func(i, j int) bool {
+		left, right := report.Violations[i], report.Violations[j]
+		if left.Delta != right.Delta {
+			return left.Delta > right.Delta
+		}
+		if left.From.Path != right.From.Path {
+			return left.From.Path < right.From.Path
+		}
+		return left.To.Path < right.To.Path
+	})
+	return report
+}
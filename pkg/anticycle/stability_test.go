@@ -0,0 +1,103 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPkg(name string, imports ...string) *model.Pkg {
+	pkg := model.NewPkg()
+	pkg.Name = name
+	pkg.Path = "/tmp/anticycle/stability/" + name
+	for _, imp := range imports {
+		name := "/tmp/anticycle/stability/" + imp
+		pkg.Imports[name] = &model.ImportInfo{Name: name, NameShort: imp}
+	}
+	return pkg
+}
+
+func TestStability(t *testing.T) {
+	packages := []*model.Pkg{
+		newTestPkg("app", "api", "core"),
+		newTestPkg("api", "core"),
+		newTestPkg("core", "helper"),
+		newTestPkg("helper", "conf"),
+		newTestPkg("conf"),
+	}
+	report := Stability(packages)
+
+	expected := []struct {
+		name               string
+		afferent, efferent int
+		instability        float64
+	}{
+		{"api", 1, 1, 0.5},
+		{"app", 0, 2, 1},
+		{"conf", 1, 0, 0},
+		{"core", 2, 1, 1.0 / 3},
+		{"helper", 1, 1, 0.5},
+	}
+	assert.Len(t, report.Packages, len(expected))
+	for i, exp := range expected {
+		t.Run(exp.name, func(t *testing.T) {
+			assert.Equal(t, exp.name, report.Packages[i].Name)
+			assert.Equal(t, exp.afferent, report.Packages[i].Afferent)
+			assert.Equal(t, exp.efferent, report.Packages[i].Efferent)
+			assert.InDelta(t, exp.instability, report.Packages[i].Instability, 0.0001)
+		})
+	}
+
+	assert.Len(t, report.Violations, 1)
+	assert.Equal(t, "core", report.Violations[0].From.Name)
+	assert.Equal(t, "helper", report.Violations[0].To.Name)
+	assert.InDelta(t, 0.5-1.0/3, report.Violations[0].Delta, 0.0001)
+}
+
+func TestStability_WithSelfImport(t *testing.T) {
+	packages := []*model.Pkg{
+		newTestPkg("app", "app", "core"),
+		newTestPkg("core", "core"),
+	}
+	report := Stability(packages)
+
+	assert.Len(t, report.Packages, 2)
+	assert.Equal(t, "app", report.Packages[0].Name)
+	assert.Equal(t, 0, report.Packages[0].Afferent)
+	assert.Equal(t, 1, report.Packages[0].Efferent)
+	assert.InDelta(t, 1, report.Packages[0].Instability, 0.0001)
+	assert.Equal(t, "core", report.Packages[1].Name)
+	assert.Equal(t, 1, report.Packages[1].Afferent)
+	assert.Equal(t, 0, report.Packages[1].Efferent)
+	assert.InDelta(t, 0, report.Packages[1].Instability, 0.0001)
+	assert.Empty(t, report.Violations)
+}
+
+func TestStability_ViolationsOrderedByDelta(t *testing.T) {
+	packages := []*model.Pkg{
+		newTestPkg("a", "b", "c"),
+		newTestPkg("b"),
+		newTestPkg("c", "d", "e"),
+		newTestPkg("d", "b"),
+		newTestPkg("e", "b"),
+		newTestPkg("x", "c"),
+		newTestPkg("y", "c"),
+	}
+	report := Stability(packages)
+
+	assert.NotEmpty(t, report.Violations)
+	for i := 1; i < len(report.Violations); i++ {
+		assert.True(t, report.Violations[i-1].Delta >= report.Violations[i].Delta)
+	}
+}
+
+func TestStability_WithEmptyInput(t *testing.T) {
+	report := Stability([]*model.Pkg{})
+	assert.Empty(t, report.Packages)
+	assert.Empty(t, report.Violations)
+}
@@ -0,0 +1,117 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"sort"
+
+	"github.com/anticycle/anticycle/internal/pkg/scan"
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Suppress marks imports of packages and files acknowledged by suppression
+// directives found by Fetch, like //anticycle:ignore attached to an import, or
+// package level //anticycle:allow-cycle with=x/y. Should be called before FindCycles,
+// so suppressed imports take no part in cycles, while other analyses still see them.
+// Suppression is active if it matches an import between packages of the same cycle,
+// otherwise it is stale.
+func Suppress(packages []*model.Pkg) *model.SuppressionReport {
+	report := &model.SuppressionReport{
+		Active: make([]*model.Suppression, 0),
+		Stale:  make([]*model.Suppression, 0),
+	}
+
+	idx := scan.NewIndex(packages)
+	component := make([]int, len(packages))
+	for compIdx, scc := range scan.StronglyConnected(scan.Dependencies(packages)) {
+		for _, node := range scc {
+			component[node] = compIdx
+			if len(scc) == 1 {
+				component[node] = -1
+			}
+		}
+	}
+
+	for pkgIdx, pkg := range packages {
+		if len(pkg.Suppressions) == 0 {
+			continue
+		}
+		targets := make([]int, len(pkg.Suppressions))
+		for i, suppression := range pkg.Suppressions {
+			targets[i] = allowedPackage(packages, suppression)
+		}
+		active := make([]bool, len(pkg.Suppressions))
+		used := make(map[string]bool, len(pkg.Imports))
+		for _, file := range pkg.Files {
+			for _, imp := range file.Imports {
+				impIdx := idx.Find(imp)
+				for i, suppression := range pkg.Suppressions {
+					if !suppresses(suppression, file, imp, impIdx, targets[i]) {
+						continue
+					}
+					imp.Suppressed = true
+					if impIdx >= 0 && component[pkgIdx] >= 0 && component[pkgIdx] == component[impIdx] {
+						active[i] = true
+					}
+				}
+				if !imp.Suppressed {
+					used[imp.Name] = true
+				}
+			}
+		}
+		// Package imports are suppressed only if no file imports them without suppression.
+		for name, imp := range pkg.Imports {
+			imp.Suppressed = !used[name]
+		}
+
+		for i, suppression := range pkg.Suppressions {
+			if active[i] {
+				report.Active = append(report.Active, suppression)
+			} else {
+				report.Stale = append(report.Stale, suppression)
+			}
+		}
+	}
+
+	sortSuppressions(report.Active)
+	sortSuppressions(report.Stale)
+	return report
+}
+
+// suppresses checks if suppression matches import of the file. Import pointing
+// to package at index impIdx matches allow-cycle directive only if the package
+// is the one given by directive, it is target.
+func suppresses(suppression *model.Suppression, file *model.File, imp *model.ImportInfo, impIdx, target int) bool {
+	switch suppression.Directive {
+	case model.DirectiveIgnore:
+		return suppression.File == file.Path && suppression.Line == imp.Line && suppression.Import == imp.Name
+	case model.DirectiveAllowCycle:
+		return target >= 0 && impIdx == target
+	}
+	return false
+}
+
+// allowedPackage returns index of package given with allow-cycle directive or -1
+// if there is no such package or it is ambiguous. Directive may give a full import
+// path or its trailing elements.
+func allowedPackage(packages []*model.Pkg, suppression *model.Suppression) int {
+	if suppression.Directive != model.DirectiveAllowCycle {
+		return -1
+	}
+	target, err := findPkg(packages, suppression.Import)
+	if err != nil {
+		return -1
+	}
+	return target
+}
+
+func sortSuppressions(suppressions []*model.Suppression) {
+	sort.SliceStable(suppressions, func(i, j int) bool {
+		if suppressions[i].File != suppressions[j].File {
+			return suppressions[i].File < suppressions[j].File
+		}
+		return suppressions[i].Line < suppressions[j].Line
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSuppressionPkg(name string, imports ...string) *model.Pkg {
+	pkg := model.NewPkg()
+	pkg.Name = name
+	pkg.Path = "m/" + name
+	pkg.ImportPath = "example.com/m/" + name
+	file := model.NewFile()
+	file.Path = pkg.Path + "/" + name + ".go"
+	for i, imp := range imports {
+		info := &model.ImportInfo{Name: imp, NameShort: imp[len("example.com/m/"):]}
+		pkg.Imports[imp] = info
+		fileImp := *info
+		fileImp.Line = i + 1
+		file.Imports = append(file.Imports, &fileImp)
+	}
+	pkg.Files = append(pkg.Files, file)
+	return pkg
+}
+
+func TestSuppress(t *testing.T) {
+	api := newSuppressionPkg("api", "example.com/m/store")
+	store := newSuppressionPkg("store", "example.com/m/api")
+	store.Suppressions = []*model.Suppression{
+		{Pkg: "store", File: "m/store/store.go", Line: 1, Directive: model.DirectiveIgnore, Import: "example.com/m/api", Reason: "callbacks"},
+	}
+	core := newSuppressionPkg("core", "example.com/m/plugin", "example.com/m/util")
+	plugin := newSuppressionPkg("plugin", "example.com/m/core")
+	plugin.Suppressions = []*model.Suppression{
+		{Pkg: "plugin", File: "m/plugin/doc.go", Line: 3, Directive: model.DirectiveAllowCycle, Import: "m/core"},
+		{Pkg: "plugin", File: "m/plugin/doc.go", Line: 4, Directive: model.DirectiveAllowCycle, Import: "m/missing"},
+	}
+	util := newSuppressionPkg("util")
+	core.Suppressions = []*model.Suppression{
+		{Pkg: "core", File: "m/core/core.go", Line: 2, Directive: model.DirectiveIgnore, Import: "example.com/m/util"},
+	}
+	packages := []*model.Pkg{api, store, core, plugin, util}
+
+	report := Suppress(packages)
+
+	assert.Equal(t, []*model.Suppression{plugin.Suppressions[0], store.Suppressions[0]}, report.Active)
+	assert.Equal(t, []*model.Suppression{core.Suppressions[0], plugin.Suppressions[1]}, report.Stale)
+
+	assert.True(t, store.Files[0].Imports[0].Suppressed)
+	assert.True(t, store.Imports["example.com/m/api"].Suppressed)
+	assert.True(t, plugin.Imports["example.com/m/core"].Suppressed)
+	assert.False(t, core.Files[0].Imports[0].Suppressed)
+	assert.True(t, core.Files[0].Imports[1].Suppressed)
+	assert.False(t, core.Imports["example.com/m/plugin"].Suppressed)
+
+	cycles, err := FindCycles(packages)
+	assert.NoError(t, err)
+	for _, pkg := range cycles {
+		assert.False(t, pkg.HaveCycle, pkg.Name)
+	}
+}
+
+func TestSuppress_WithImportInOtherFile(t *testing.T) {
+	bar := newSuppressionPkg("bar", "example.com/m/baz")
+	bar.Files = append(bar.Files, &model.File{
+		Path:    "m/bar/other.go",
+		Imports: []*model.ImportInfo{{Name: "example.com/m/baz", NameShort: "baz", Line: 5}},
+	})
+	bar.Suppressions = []*model.Suppression{
+		{Pkg: "bar", File: "m/bar/bar.go", Line: 1, Directive: model.DirectiveIgnore, Import: "example.com/m/baz"},
+	}
+	baz := newSuppressionPkg("baz", "example.com/m/bar")
+
+	report := Suppress([]*model.Pkg{bar, baz})
+
+	assert.Len(t, report.Active, 1)
+	assert.True(t, bar.Files[0].Imports[0].Suppressed)
+	assert.False(t, bar.Files[1].Imports[0].Suppressed)
+	assert.False(t, bar.Imports["example.com/m/baz"].Suppressed)
+}
+
+func TestSuppress_WithAllowCycleAndImportOfSameName(t *testing.T) {
+	bar := newSuppressionPkg("bar", "example.com/m/baz")
+	baz := newSuppressionPkg("baz", "example.com/m/bar", "example.com/lib/bar")
+	baz.Files[0].Imports[1].NameShort = "bar"
+	baz.Imports["example.com/lib/bar"].NameShort = "bar"
+	baz.Suppressions = []*model.Suppression{
+		{Pkg: "baz", File: "m/baz/doc.go", Line: 1, Directive: model.DirectiveAllowCycle, Import: "bar"},
+	}
+
+	report := Suppress([]*model.Pkg{bar, baz})
+
+	assert.Len(t, report.Active, 1)
+	assert.True(t, baz.Files[0].Imports[0].Suppressed)
+	assert.False(t, baz.Files[0].Imports[1].Suppressed)
+	assert.False(t, baz.Imports["example.com/lib/bar"].Suppressed)
+}
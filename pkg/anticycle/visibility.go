@@ -0,0 +1,82 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Visibility lists imports which violate visibility rule of internal packages.
+// Package "a/b/internal/c" may be imported only by packages rooted at "a/b".
+// Importing package is identified by its import path, or by its absolute
+// directory if package is outside of any module, so the code does not need to compile.
+func Visibility(packages []*model.Pkg) *model.VisibilityReport {
+	report := &model.VisibilityReport{
+		Violations: make([]*model.VisibilityViolation, 0),
+	}
+
+	for _, pkg := range packages {
+		dir, err := filepath.Abs(pkg.Path)
+		if err != nil {
+			dir = pkg.Path
+		}
+		dir = filepath.ToSlash(dir)
+
+		for _, file := range pkg.Files {
+			for _, imp := range file.Imports {
+				parent, ok := internalParent(imp.Name)
+				if !ok || canImportInternal(pkg.ImportPath, dir, parent) {
+					continue
+				}
+				report.Violations = append(report.Violations, &model.VisibilityViolation{
+					Pkg:    pkg.Name,
+					File:   file.Path,
+					Import: imp,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(report.Violations, func(i, j int) bool {
+		left, right := report.Violations[i], report.Violations[j]
+		if left.File != right.File {
+			return left.File < right.File
+		}
+		return left.Import.Line < right.Import.Line
+	})
+	return report
+}
+
+// internalParent returns path of the directory which contains the last internal
+// element of given import path, e.g. "a/b" for "a/b/internal/c".
+func internalParent(importPath string) (string, bool) {
+	switch {
+	case strings.HasPrefix(importPath, "internal/") || importPath == "internal":
+		return "", true
+	case strings.HasSuffix(importPath, "/internal"):
+		return strings.TrimSuffix(importPath, "/internal"), true
+	}
+	if i := strings.LastIndex(importPath, "/internal/"); i >= 0 {
+		return importPath[:i], true
+	}
+	return "", false
+}
+
+// canImportInternal checks if importer lays in the tree rooted at parent.
+// Without import path the absolute directory of importer must contain parent,
+// which is the case for packages in GOPATH.
+func canImportInternal(importPath, dir, parent string) bool {
+	if parent == "" {
+		return false
+	}
+	if importPath == parent || strings.HasPrefix(importPath, parent+"/") {
+		return true
+	}
+	return strings.Contains(dir+"/", "/"+parent+"/")
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newVisibilityPkg(name, path, importPath string, imports ...string) *model.Pkg {
+	pkg := model.NewPkg()
+	pkg.Name = name
+	pkg.Path = path
+	pkg.ImportPath = importPath
+	file := model.NewFile()
+	file.Path = path + "/" + name + ".go"
+	for i, imp := range imports {
+		file.Imports = append(file.Imports, &model.ImportInfo{Name: imp, Line: i + 1})
+	}
+	pkg.Files = append(pkg.Files, file)
+	return pkg
+}
+
+func TestVisibility(t *testing.T) {
+	packages := []*model.Pkg{
+		newVisibilityPkg("a", "/tmp/anticycle/visibility/a", "example.com/m/a",
+			"example.com/m/a/internal/c"),
+		newVisibilityPkg("b", "/tmp/anticycle/visibility/a/b", "example.com/m/a/b",
+			"example.com/m/a/internal/c", "example.com/m/internal/d"),
+		newVisibilityPkg("app", "/tmp/anticycle/visibility/app", "example.com/m/app",
+			"fmt", "example.com/m/a", "example.com/m/a/internal/c", "internal/poll", "example.com/m/internal"),
+		newVisibilityPkg("ab", "/tmp/anticycle/visibility/ab", "example.com/m/ab",
+			"example.com/m/a/internal/c"),
+	}
+	report := Visibility(packages)
+
+	result := make([]string, 0)
+	for _, v := range report.Violations {
+		result = append(result, v.Pkg+" "+v.Import.Name)
+	}
+	expected := []string{
+		"ab example.com/m/a/internal/c",
+		"app example.com/m/a/internal/c",
+		"app internal/poll",
+	}
+	assert.Equal(t, expected, result)
+}
+
+func TestVisibility_WithoutModule(t *testing.T) {
+	packages := []*model.Pkg{
+		newVisibilityPkg("b", "/go/src/example.com/m/a/b", "", "example.com/m/a/internal/c"),
+		newVisibilityPkg("app", "/go/src/example.com/m/app", "", "example.com/m/a/internal/c"),
+	}
+	report := Visibility(packages)
+
+	assert.Len(t, report.Violations, 1)
+	assert.Equal(t, "app", report.Violations[0].Pkg)
+	assert.Equal(t, "/go/src/example.com/m/app/app.go", report.Violations[0].File)
+	assert.Equal(t, 1, report.Violations[0].Import.Line)
+}
+
+func TestInternalParent(t *testing.T) {
+	tests := []struct {
+		importPath, parent string
+		ok                 bool
+	}{
+		{"a/b/internal/c", "a/b", true},
+		{"a/b/internal", "a/b", true},
+		{"a/internal/b/internal/c", "a/internal/b", true},
+		{"internal/poll", "", true},
+		{"a/b/internals/c", "", false},
+		{"fmt", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.importPath, func(t *testing.T) {
+			parent, ok := internalParent(tt.importPath)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.parent, parent)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"github.com/anticycle/anticycle/internal/pkg/scan"
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Watcher keeps parsed files of a directory in memory and finds cycles
+// introduced or resolved since the previous check. Only directories
+// with changed .go files or go.mod are parsed again.
+type Watcher struct {
+	tree     *scan.Tree
+	kinds    []string
+	analysis *model.Analysis
+}
+
+// NewWatcher creates watcher of the directory. Only imports of given kinds
+// are analyzed, all kinds if the list is empty.
+func NewWatcher(dir string, excludedDir []string, kinds []string) *Watcher {
+	return &Watcher{tree: scan.NewTree(dir, excludedDir), kinds: kinds}
+}
+
+// Check parses changed directories and compares cycles with the previous check.
+// The first check reports all cycles as new. Report is nil if nothing changed
+// since the previous check. Suppression directives are applied.
+func (w *Watcher) Check() (*model.BaselineReport, error) {
+	changed, err := w.tree.Refresh()
+	if err != nil {
+		return nil, err
+	}
+	if !changed && w.analysis != nil {
+		return nil, nil
+	}
+
+	packages := w.tree.Packages()
+	if len(w.kinds) > 0 {
+		packages, err = FilterKinds(packages, w.kinds)
+		if err != nil {
+			return nil, err
+		}
+	}
+	Suppress(packages)
+	cycles, err := FindCycles(packages)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := Analyze(OnlyAffected(cycles))
+	report := Baseline(analysis, w.analysis)
+	w.analysis = analysis
+	return report, nil
+}
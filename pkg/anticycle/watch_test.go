@@ -0,0 +1,70 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/anticycle/anticycle/internal/pkg/fixtures"
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcher_Check(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anticycle-watch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, fixtures.WriteSource(dir, "foo", "package foo"))
+	assert.NoError(t, fixtures.WriteSource(dir, "bar", fmt.Sprintf("package bar\nimport \"%s/baz\"", dir)))
+	assert.NoError(t, fixtures.WriteSource(dir, "baz", fmt.Sprintf("package baz\nimport \"%s/bar\"", dir)))
+
+	watcher := NewWatcher(dir, []string{}, []string{})
+	report, err := watcher.Check()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"bar", "baz", "bar"}}, report.New)
+	assert.Empty(t, report.Fixed)
+
+	report, err = watcher.Check()
+	assert.NoError(t, err)
+	assert.Nil(t, report)
+
+	assert.NoError(t, fixtures.WriteSource(dir, "foo", fmt.Sprintf("package foo\nimport \"%s/bar\"", dir)))
+	assert.NoError(t, fixtures.WriteSource(dir, "bar", fmt.Sprintf("package bar\nimport \"%s/foo\"", dir)))
+	report, err = watcher.Check()
+	assert.NoError(t, err)
+	expected := &model.BaselineReport{
+		New:      [][]string{{"bar", "foo", "bar"}},
+		Existing: [][]string{},
+		Fixed:    [][]string{{"bar", "baz", "bar"}},
+	}
+	assert.Equal(t, expected, report)
+}
+
+func TestWatcher_Check_WhenSyntaxError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anticycle-watch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, fixtures.WriteSource(dir, "bar", fmt.Sprintf("package bar\nimport \"%s/baz\"", dir)))
+	assert.NoError(t, fixtures.WriteSource(dir, "baz", fmt.Sprintf("package baz\nimport \"%s/bar\"", dir)))
+
+	watcher := NewWatcher(dir, []string{}, []string{})
+	_, err = watcher.Check()
+	assert.NoError(t, err)
+
+	assert.NoError(t, fixtures.WriteSource(dir, "baz", "package baz\nimport ("))
+	_, err = watcher.Check()
+	assert.Error(t, err)
+
+	assert.NoError(t, fixtures.WriteSource(dir, "baz", "package baz"))
+	report, err := watcher.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, report.New)
+	assert.Equal(t, [][]string{{"bar", "baz", "bar"}}, report.Fixed)
+}
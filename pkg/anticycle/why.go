@@ -0,0 +1,116 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anticycle/anticycle/internal/pkg/scan"
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Why finds the shortest chain of imports from one package to another, which
+// explains why the first package depends on the other. Packages are given by name,
+// path, path suffix or import path. If both are the same package, the chain is
+// the shortest cycle through it. Chain is nil if there is no such chain.
+// Will return error if any of packages is not found or is ambiguous.
+func Why(packages []*model.Pkg, from, to string) (*model.ImportChain, error) {
+	start, err := findPkg(packages, from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := findPkg(packages, to)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := shortestChain(scan.Dependencies(packages), start, end)
+	if steps == nil {
+		return nil, nil
+	}
+
+	idx := scan.NewIndex(packages)
+	chain := &model.ImportChain{
+		Packages: make([]string, 0, len(steps)),
+		Links:    make([]*model.ImportLink, 0, len(steps)-1),
+	}
+	for i, step := range steps {
+		chain.Packages = append(chain.Packages, packages[step].Path)
+		if i > 0 {
+			chain.Links = append(chain.Links, importLink(packages[steps[i-1]], step, idx))
+		}
+	}
+	return chain, nil
+}
+
+// shortestChain returns indexes of packages on the shortest chain of imports
+// from start to end, including both. If start is the same as end, the chain
+// is the shortest cycle back to start. Chain is nil if end is not reachable.
+func shortestChain(deps [][]int, start, end int) []int {
+	// Breadth-first search finds the shortest chain, dependencies are sorted
+	// by index, so the chain is deterministic. Start is not visited upfront
+	// if it is the end, so the search may come back to it.
+	previous := make([]int, len(deps))
+	for i := range previous {
+		previous[i] = -1
+	}
+	if start != end {
+		previous[start] = start
+	}
+	queue := []int{start}
+	for len(queue) > 0 && previous[end] < 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range deps[current] {
+			if previous[next] < 0 {
+				previous[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	if previous[end] < 0 {
+		return nil
+	}
+
+	steps := []int{end}
+	for len(steps) == 1 || steps[0] != start {
+		steps = append([]int{previous[steps[0]]}, steps...)
+	}
+	return steps
+}
+
+// findPkg returns index of the only package which matches the name.
+func findPkg(packages []*model.Pkg, name string) (int, error) {
+	found := make([]int, 0, 1)
+	for i, pkg := range packages {
+		if pkg.ImportPath == name || isAllowed(pkg, []string{name}) {
+			found = append(found, i)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return -1, fmt.Errorf("package '%v' is not found", name)
+	case 1:
+		return found[0], nil
+	}
+	paths := make([]string, 0, len(found))
+	for _, i := range found {
+		paths = append(paths, packages[i].Path)
+	}
+	return -1, fmt.Errorf("package '%v' is ambiguous, try one of '%v'", name, strings.Join(paths, "', '"))
+}
+
+// importLink returns the first import of the package at index next in files of pkg.
+func importLink(pkg *model.Pkg, next int, idx *scan.Index) *model.ImportLink {
+	for _, file := range pkg.Files {
+		for _, imp := range file.Imports {
+			if idx.Find(imp) == next {
+				return &model.ImportLink{Pkg: pkg.Name, File: file.Path, Import: imp}
+			}
+		}
+	}
+	return &model.ImportLink{Pkg: pkg.Name}
+}
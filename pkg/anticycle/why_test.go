@@ -0,0 +1,89 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package anticycle
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhy(t *testing.T) {
+	app := newTestPkg("app", "api", "core")
+	app.Files = []*model.File{
+		{Path: "/tmp/anticycle/stability/app/app.go", Imports: []*model.ImportInfo{app.Imports["/tmp/anticycle/stability/api"]}},
+		{Path: "/tmp/anticycle/stability/app/main.go", Imports: []*model.ImportInfo{app.Imports["/tmp/anticycle/stability/core"]}},
+	}
+	packages := []*model.Pkg{
+		app,
+		newTestPkg("api", "core"),
+		newTestPkg("core", "helper"),
+		newTestPkg("helper", "conf"),
+		newTestPkg("conf"),
+	}
+
+	chain, err := Why(packages, "app", "stability/helper")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"/tmp/anticycle/stability/app",
+		"/tmp/anticycle/stability/core",
+		"/tmp/anticycle/stability/helper",
+	}, chain.Packages)
+	assert.Equal(t, &model.ImportLink{
+		Pkg:    "app",
+		File:   "/tmp/anticycle/stability/app/main.go",
+		Import: app.Imports["/tmp/anticycle/stability/core"],
+	}, chain.Links[0])
+	assert.Equal(t, &model.ImportLink{Pkg: "core"}, chain.Links[1])
+}
+
+func TestWhy_WithoutChain(t *testing.T) {
+	packages := []*model.Pkg{
+		newTestPkg("app", "core"),
+		newTestPkg("core"),
+	}
+
+	chain, err := Why(packages, "core", "app")
+	assert.NoError(t, err)
+	assert.Nil(t, chain)
+
+	chain, err = Why(packages, "app", "app")
+	assert.NoError(t, err)
+	assert.Nil(t, chain)
+}
+
+func TestWhy_WithSamePackage(t *testing.T) {
+	packages := []*model.Pkg{
+		newTestPkg("app", "core"),
+		newTestPkg("core", "app"),
+	}
+
+	chain, err := Why(packages, "app", "app")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/tmp/anticycle/stability/app", "/tmp/anticycle/stability/core",
+		"/tmp/anticycle/stability/app"}, chain.Packages)
+	assert.Len(t, chain.Links, 2)
+}
+
+func TestWhy_WithWrongPackages(t *testing.T) {
+	other := newTestPkg("core")
+	other.Path = "/tmp/anticycle/other/core"
+	packages := []*model.Pkg{
+		newTestPkg("app", "core"),
+		newTestPkg("core"),
+		other,
+	}
+
+	_, err := Why(packages, "lib", "app")
+	assert.EqualError(t, err, "package 'lib' is not found")
+	_, err = Why(packages, "app", "core")
+	assert.EqualError(t, err, "package 'core' is ambiguous, try one of "+
+		"'/tmp/anticycle/stability/core', '/tmp/anticycle/other/core'")
+
+	chain, err := Why(packages, "app", "stability/core")
+	assert.NoError(t, err)
+	assert.Len(t, chain.Links, 1)
+}
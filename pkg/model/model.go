@@ -8,26 +8,111 @@ package model
 import (
 	"go/ast"
 	"path"
+	"sort"
 	"strings"
 )
 
+// SchemaVersion is a version of JSON representation of Analysis.
+// It is incremented whenever fields are added or change their meaning.
+// Version 1 holds packages and metadata, version 2 adds tool, root,
+// timestamp and options of the run, version 3 adds suppressions,
+// version 4 adds allowlist, version 5 adds owners, version 6 marks
+// suppressed imports.
+const SchemaVersion = 6
+
+// Kinds of imports.
+const (
+	// KindStdlib is a package of standard library found in GOROOT.
+	KindStdlib = "stdlib"
+	// KindModule is a package of the same module as importing package.
+	KindModule = "module"
+	// KindLocal is a package of other module found in analyzed directory.
+	KindLocal = "local"
+	// KindExternal is a third-party package, or a package of standard library
+	// if GOROOT is not available.
+	KindExternal = "external"
+	// KindPseudo is a pseudo package like "C" or "unsafe".
+	KindPseudo = "pseudo"
+)
+
+// Suppression directives, written in comments like //anticycle:ignore.
+const (
+	// DirectiveIgnore is attached to an import and suppresses only this import.
+	DirectiveIgnore = "ignore"
+	// DirectiveAllowCycle is a package level directive which suppresses
+	// all imports of the package given with "with=" argument.
+	DirectiveAllowCycle = "allow-cycle"
+)
+
+// Operations of simulated edits.
+const (
+	// EditMove moves a file to package in the target directory.
+	EditMove = "move"
+	// EditRemove removes the target import from a file.
+	EditRemove = "remove"
+	// EditAdd adds the target import to a file.
+	EditAdd = "add"
+)
+
 type (
 	// AnalysisMeta is a metadata produced based on Analysis.
 	AnalysisMeta struct {
 		Cycles [][]string `json:"cycles"`
 	}
 
+	// Tool identifies anticycle build which produced the analysis.
+	Tool struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Build   string `json:"build"`
+	}
+
+	// Options holds options of the run which produced the analysis.
+	Options struct {
+		All          bool     `json:"all"`
+		Excluded     []string `json:"excluded"`
+		Kinds        []string `json:"kinds"`
+		Stability    bool     `json:"stability"`
+		Levels       bool     `json:"levels"`
+		Orphans      bool     `json:"orphans"`
+		AllowOrphans []string `json:"allowOrphans"`
+		Visibility   bool     `json:"visibility"`
+		Baseline     string   `json:"baseline"`
+		Owners       bool     `json:"owners"`
+		Teams        bool     `json:"teams"`
+	}
+
 	// Analysis holds final anticycle output.
+	// SchemaVersion is zero for reports created before versioning.
+	// Tool, Root, Timestamp and Options describe the run and are set by the command line tool.
 	Analysis struct {
-		Cycles   []*Pkg        `json:"cycles"`
-		Metadata *AnalysisMeta `json:"metadata"`
+		SchemaVersion int                `json:"schemaVersion,omitempty"`
+		Tool          *Tool              `json:"tool,omitempty"`
+		Root          string             `json:"root,omitempty"`
+		Timestamp     string             `json:"timestamp,omitempty"`
+		Options       *Options           `json:"options,omitempty"`
+		Cycles        []*Pkg             `json:"cycles"`
+		Metadata      *AnalysisMeta      `json:"metadata"`
+		Stability     *StabilityReport   `json:"stability,omitempty"`
+		Levels        *Levelization      `json:"levels,omitempty"`
+		Orphans       *OrphanReport      `json:"orphans,omitempty"`
+		Visibility    *VisibilityReport  `json:"visibility,omitempty"`
+		Baseline      *BaselineReport    `json:"baseline,omitempty"`
+		Suppressions  *SuppressionReport `json:"suppressions,omitempty"`
+		Allowlist     *AllowlistReport   `json:"allowlist,omitempty"`
+		Owners        *OwnersReport      `json:"owners,omitempty"`
 	}
 
 	// ImportInfo holds information about import statements.
+	// Line is set only for imports of a File. Kind is one of Kind constants.
+	// Suppressed imports are acknowledged by directives and take no part in cycles.
 	ImportInfo struct {
-		Name      string  `json:"name"`
-		NameShort string  `json:"nameShort"`
-		Alias     *string `json:"alias"`
+		Name       string  `json:"name"`
+		NameShort  string  `json:"nameShort"`
+		Alias      *string `json:"alias"`
+		Kind       string  `json:"kind,omitempty"`
+		Line       int     `json:"line,omitempty"`
+		Suppressed bool    `json:"suppressed,omitempty"`
 	}
 
 	// File is a representation of source file with its path and list of imports.
@@ -37,22 +122,314 @@ type (
 	}
 
 	// Pkg is a higher level structure which has all information about its files and imports.
+	// ImportPath and Module are resolved from the nearest go.mod file and are empty outside of modules.
 	Pkg struct {
-		Name      string                 `json:"name"`
-		Path      string                 `json:"path"`
-		Imports   map[string]*ImportInfo `json:"imports"`
-		Files     []*File                `json:"files"`
-		Cycles    []*Cycle               `json:"cycles,omitempty"`
-		HaveCycle bool                   `json:"haveCycle"`
+		Name         string                 `json:"name"`
+		Path         string                 `json:"path"`
+		ImportPath   string                 `json:"importPath,omitempty"`
+		Module       string                 `json:"module,omitempty"`
+		Imports      map[string]*ImportInfo `json:"imports"`
+		Files        []*File                `json:"files"`
+		Cycles       []*Cycle               `json:"cycles,omitempty"`
+		HaveCycle    bool                   `json:"haveCycle"`
+		Suppressions []*Suppression         `json:"suppressions,omitempty"`
 	}
 
-	// Cycle holds information about affected file and import
+	// Cycle holds information about affected file and import.
+	// Owners of the affected file are set only if owners are analyzed.
 	Cycle struct {
 		AffectedImport *ImportInfo `json:"affectedImport"`
 		AffectedFile   string      `json:"affectedFile"`
+		Owners         []string    `json:"owners,omitempty"`
+	}
+
+	// Stability holds coupling metrics of a package.
+	// Afferent is a number of packages which import the package,
+	// Efferent is a number of packages imported by the package
+	// and Instability is Efferent / (Afferent + Efferent).
+	Stability struct {
+		Name        string  `json:"name"`
+		Path        string  `json:"path"`
+		Afferent    int     `json:"afferent"`
+		Efferent    int     `json:"efferent"`
+		Instability float64 `json:"instability"`
+	}
+
+	// SDPViolation is an import edge where stable package depends on less stable one.
+	// Delta is a difference of instability between both packages.
+	SDPViolation struct {
+		From  *Stability `json:"from"`
+		To    *Stability `json:"to"`
+		Delta float64    `json:"delta"`
+	}
+
+	// Component is a group of packages which import each other,
+	// collapsed into single node of dependency graph.
+	Component struct {
+		Packages []string `json:"packages"`
+		Cycle    bool     `json:"cycle"`
+	}
+
+	// Layer holds components which depend only on components from lower layers.
+	Layer struct {
+		Level      int          `json:"level"`
+		Components []*Component `json:"components"`
+	}
+
+	// Levelization holds layers of packages, starting from level 0 which imports
+	// nothing from analyzed packages, and the longest chain of dependent components.
+	Levelization struct {
+		Layers       []*Layer     `json:"layers"`
+		LongestChain []*Component `json:"longestChain"`
+	}
+
+	// Orphan is a package which is not imported by any other analyzed package.
+	Orphan struct {
+		Name string `json:"name"`
+		Path string `json:"path"`
+	}
+
+	// OrphanReport holds orphan packages and orphans which are allowed to be unused.
+	OrphanReport struct {
+		Packages []*Orphan `json:"packages"`
+		Allowed  []*Orphan `json:"allowed"`
+	}
+
+	// VisibilityViolation is an import of internal package from outside of
+	// the tree rooted at the parent of internal directory.
+	VisibilityViolation struct {
+		Pkg    string      `json:"pkg"`
+		File   string      `json:"file"`
+		Import *ImportInfo `json:"import"`
+	}
+
+	// VisibilityReport holds imports which violate visibility of internal packages.
+	VisibilityReport struct {
+		Violations []*VisibilityViolation `json:"violations"`
+	}
+
+	// BaselineReport compares cycles with cycles of a baseline analysis.
+	// New cycles are not present in the baseline, Existing are present in both
+	// and Fixed are present only in the baseline.
+	BaselineReport struct {
+		New      [][]string `json:"new"`
+		Existing [][]string `json:"existing"`
+		Fixed    [][]string `json:"fixed"`
+	}
+
+	// Suppression is a directive comment which acknowledges an intentional import.
+	// Import is a name of suppressed import, Line is a line of the import for
+	// DirectiveIgnore and a line of the comment for DirectiveAllowCycle.
+	Suppression struct {
+		Pkg       string `json:"pkg"`
+		File      string `json:"file"`
+		Line      int    `json:"line"`
+		Directive string `json:"directive"`
+		Import    string `json:"import"`
+		Reason    string `json:"reason"`
+	}
+
+	// SuppressionReport holds suppressions of imports which would take part in cycles
+	// and stale suppressions, which do not match any import in a cycle.
+	SuppressionReport struct {
+		Active []*Suppression `json:"active"`
+		Stale  []*Suppression `json:"stale"`
+	}
+
+	// Config is a configuration of the analysis, read from .anticycle.json file.
+	Config struct {
+		AllowedCycles []*AllowedCycle `json:"allowedCycles"`
+	}
+
+	// AllowedCycle is a cycle which is temporarily allowed, e.g. during a refactoring.
+	// Cycle lists names of packages in the cycle, in any order. Expires is the last
+	// day, in YYYY-MM-DD format, when the cycle is allowed.
+	AllowedCycle struct {
+		Cycle   []string `json:"cycle"`
+		Owner   string   `json:"owner"`
+		Expires string   `json:"expires"`
+		Reason  string   `json:"reason,omitempty"`
+	}
+
+	// AllowlistReport compares cycles with allowed cycles of the configuration.
+	// Allowed cycles are reported as warnings, Expired and NotAllowed cycles fail the run.
+	// Unused are allowed cycles which are not found in the analysis.
+	AllowlistReport struct {
+		Allowed    []*AllowedCycle `json:"allowed"`
+		Expired    []*AllowedCycle `json:"expired"`
+		NotAllowed [][]string      `json:"notAllowed"`
+		Unused     []*AllowedCycle `json:"unused"`
+	}
+
+	// CodeOwners holds rules of CODEOWNERS file in GitHub or GitLab syntax.
+	// Patterns match paths relative to Root, the directory of the repository.
+	CodeOwners struct {
+		File  string       `json:"file"`
+		Root  string       `json:"root"`
+		Rules []*OwnerRule `json:"rules"`
+	}
+
+	// OwnerRule assigns owners to files which match the pattern. Rule without
+	// owners removes owners of matched files. Section is a name of GitLab section,
+	// empty for rules outside of sections.
+	OwnerRule struct {
+		Pattern string   `json:"pattern"`
+		Owners  []string `json:"owners"`
+		Section string   `json:"section,omitempty"`
+		Line    int      `json:"line"`
+	}
+
+	// OwnersReport describes owners of files, read from CODEOWNERS File.
+	// Owners of imports in cycles are set in Cycle.Owners, and Unowned lists
+	// affected files without owners. Teams and TeamCycles are set if the graph
+	// is aggregated by owners.
+	OwnersReport struct {
+		File       string      `json:"file"`
+		Unowned    []string    `json:"unowned"`
+		Teams      []*TeamEdge `json:"teams,omitempty"`
+		TeamCycles [][]string  `json:"teamCycles,omitempty"`
+	}
+
+	// TeamEdge aggregates imports from files owned by one owner to packages with files
+	// owned by another owner. Cycle is true if owners depend on each other.
+	TeamEdge struct {
+		From    string `json:"from"`
+		To      string `json:"to"`
+		Imports int    `json:"imports"`
+		Cycle   bool   `json:"cycle"`
+	}
+
+	// Edge is an import between two packages identified by path.
+	Edge struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+
+	// ImportLink is an import of the next package of ImportChain, with the first file which imports it.
+	ImportLink struct {
+		Pkg    string      `json:"pkg"`
+		File   string      `json:"file"`
+		Import *ImportInfo `json:"import"`
+	}
+
+	// ImportChain is the shortest chain of imports from one package to another.
+	// Packages lists paths of packages in the chain, Links holds an import per step.
+	ImportChain struct {
+		Packages []string      `json:"packages"`
+		Links    []*ImportLink `json:"links"`
+	}
+
+	// CycleChange is a cycle which gained or lost packages.
+	CycleChange struct {
+		Old []string `json:"old"`
+		New []string `json:"new"`
+	}
+
+	// DiffReport holds differences between two analyses. New cycle is changed when
+	// it shares packages with an old cycle which is gone from the new analysis and
+	// not yet paired with other new cycle, otherwise it is added. Old cycles gone
+	// and left unpaired are removed. Packages are identified by import path, or by
+	// path relative to the root of the analysis.
+	DiffReport struct {
+		Added         [][]string     `json:"added"`
+		Removed       [][]string     `json:"removed"`
+		Changed       []*CycleChange `json:"changed"`
+		EnteredCycles []string       `json:"enteredCycles"`
+		LeftCycles    []string       `json:"leftCycles"`
+		NewEdges      []*Edge        `json:"newEdges"`
+	}
+
+	// Edit is a change of the source code applied to packages by simulation.
+	// Op is one of Edit constants, Target is a directory for EditMove
+	// and an import path for EditRemove and EditAdd.
+	Edit struct {
+		Op     string `json:"op"`
+		File   string `json:"file"`
+		Target string `json:"target"`
+	}
+
+	// SimulationReport compares cycles before and after edits. Cycles are listed
+	// once, regardless of package they start from.
+	SimulationReport struct {
+		Edits      []*Edit    `json:"edits"`
+		Before     [][]string `json:"before"`
+		After      [][]string `json:"after"`
+		Resolved   [][]string `json:"resolved"`
+		Introduced [][]string `json:"introduced"`
+	}
+
+	// SplitPlan proposes how files of a package could be moved to a new package
+	// to break cycles of the package. Files holds dependencies between files
+	// of the package, one element for every file.
+	SplitPlan struct {
+		Pkg       string           `json:"pkg"`
+		Path      string           `json:"path"`
+		Cycles    [][]string       `json:"cycles"`
+		Files     []*FileDeps      `json:"files"`
+		Proposals []*SplitProposal `json:"proposals"`
+	}
+
+	// FileDeps holds files of the same package which declare identifiers used by the file.
+	FileDeps struct {
+		File      string   `json:"file"`
+		DependsOn []string `json:"dependsOn"`
+	}
+
+	// SplitProposal moves files which import a package in a cycle, with files which
+	// use them, to the Target directory. Edits can be passed to simulate command,
+	// Resolved and Introduced are cycles changed by the edits. Whole is true
+	// if all files of the package would be moved, so split is not possible.
+	SplitProposal struct {
+		Import     string     `json:"import"`
+		Target     string     `json:"target"`
+		Files      []string   `json:"files"`
+		Whole      bool       `json:"whole"`
+		Edits      []*Edit    `json:"edits"`
+		Resolved   [][]string `json:"resolved"`
+		Introduced [][]string `json:"introduced"`
+	}
+
+	// StabilityReport holds stability of packages and violations of Stable Dependencies Principle.
+	StabilityReport struct {
+		Packages   []*Stability    `json:"packages"`
+		Violations []*SDPViolation `json:"violations"`
 	}
 )
 
+// CycleKey identifies a cycle regardless of package it starts from.
+// Packages which only lead to the cycle are not a part of the key.
+func CycleKey(cycle []string) string {
+	if len(cycle) == 0 {
+		return ""
+	}
+	start := 0
+	last := cycle[len(cycle)-1]
+	for i, name := range cycle {
+		if name == last {
+			start = i
+			break
+		}
+	}
+	members := make([]string, 0, len(cycle))
+	for _, name := range cycle[start:] {
+		if !SliceContains(members, name) {
+			members = append(members, name)
+		}
+	}
+	sort.Strings(members)
+	return strings.Join(members, " ")
+}
+
+// SliceContains checks if slice holds the string.
+func SliceContains(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
 // NewPkg creates new Pkg with empty imports, files and cycles arrays.
 // Will return pointer to Pkg structure.
 func NewPkg() *Pkg {
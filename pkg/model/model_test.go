@@ -52,3 +52,10 @@ func TestNewImportInfo_IfAstImportSpecIsNil(t *testing.T) {
 	importInfo := NewImportInfo(nil)
 	assert.Nil(t, importInfo)
 }
+
+func TestCycleKey(t *testing.T) {
+	assert.Equal(t, "bar baz foo", CycleKey([]string{"foo", "bar", "baz", "foo"}))
+	assert.Equal(t, "bar baz foo", CycleKey([]string{"baz", "foo", "bar", "baz"}))
+	assert.Equal(t, "bar baz", CycleKey([]string{"foo", "bar", "baz", "bar"}))
+	assert.Equal(t, "", CycleKey([]string{}))
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Finding is a single import which takes part in a cycle, with a message
+// for annotations of the source code, e.g. Checkstyle or editor diagnostics.
+type Finding struct {
+	File    string
+	Line    int
+	Message string
+}
+
+// CycleFindings lists imports which take part in cycles, sorted by file and line.
+func CycleFindings(analysis *model.Analysis) []*Finding {
+	findings := make([]*Finding, 0)
+	for _, pkg := range analysis.Cycles {
+		paths := pkgCyclePaths(analysis, pkg)
+		for _, c := range pkg.Cycles {
+			message := fmt.Sprintf("import %q creates a cycle", c.AffectedImport.Name)
+			if len(paths) > 0 {
+				message = fmt.Sprintf("%s: %s", message, strings.Join(paths, ", "))
+			}
+			findings = append(findings, &Finding{
+				File:    c.AffectedFile,
+				Line:    c.AffectedImport.Line,
+				Message: message,
+			})
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings
+}
+
+type (
+	checkstyleReport struct {
+		XMLName xml.Name         `xml:"checkstyle"`
+		Version string           `xml:"version,attr"`
+		Files   []checkstyleFile `xml:"file"`
+	}
+
+	checkstyleFile struct {
+		Name   string            `xml:"name,attr"`
+		Errors []checkstyleError `xml:"error"`
+	}
+
+	checkstyleError struct {
+		Line     int    `xml:"line,attr,omitempty"`
+		Severity string `xml:"severity,attr"`
+		Message  string `xml:"message,attr"`
+		Source   string `xml:"source,attr"`
+	}
+)
+
+// ToCheckstyle takes cycle analysis and produces Checkstyle XML report
+// with an error for each import which creates a cycle.
+func ToCheckstyle(analysis *model.Analysis) (string, error) {
+	report := checkstyleReport{
+		Version: "4.3",
+		Files:   make([]checkstyleFile, 0),
+	}
+	for _, f := range CycleFindings(analysis) {
+		if len(report.Files) == 0 || report.Files[len(report.Files)-1].Name != f.File {
+			report.Files = append(report.Files, checkstyleFile{Name: f.File})
+		}
+		file := &report.Files[len(report.Files)-1]
+		file.Errors = append(file.Errors, checkstyleError{
+			Line:     f.Line,
+			Severity: "error",
+			Message:  f.Message,
+			Source:   "anticycle.ImportCycle",
+		})
+	}
+
+	return marshalXML(report)
+}
+
+// ToGitHub takes cycle analysis and produces GitHub Actions workflow commands,
+// which are displayed as annotations of affected lines in pull requests.
+func ToGitHub(analysis *model.Analysis) (string, error) {
+	lines := make([]string, 0)
+	for _, f := range CycleFindings(analysis) {
+		props := fmt.Sprintf("file=%s", escapeGitHubProperty(f.File))
+		if f.Line > 0 {
+			props = fmt.Sprintf("%s,line=%d", props, f.Line)
+		}
+		props = fmt.Sprintf("%s,title=Import cycle", props)
+		lines = append(lines, fmt.Sprintf("::error %s::%s", props, escapeGitHubData(f.Message)))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// escapeGitHubData escapes message of workflow command.
+func escapeGitHubData(s string) string {
+	s = strings.Replace(s, "%", "%25", -1)
+	s = strings.Replace(s, "\r", "%0D", -1)
+	return strings.Replace(s, "\n", "%0A", -1)
+}
+
+// escapeGitHubProperty escapes property value of workflow command.
+func escapeGitHubProperty(s string) string {
+	s = escapeGitHubData(s)
+	s = strings.Replace(s, ":", "%3A", -1)
+	return strings.Replace(s, ",", "%2C", -1)
+}
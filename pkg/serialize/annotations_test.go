@@ -0,0 +1,77 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAnnotationsAnalysis() *model.Analysis {
+	bar := newDiagramPkg("bar", "app", "app/baz")
+	bar.HaveCycle = true
+	bar.Cycles = []*model.Cycle{{
+		AffectedImport: &model.ImportInfo{Name: "app/baz", NameShort: "baz", Line: 5},
+		AffectedFile:   "app/bar/bar.go",
+	}}
+	baz := newDiagramPkg("baz", "app", "app/bar")
+	baz.HaveCycle = true
+	baz.Cycles = []*model.Cycle{{
+		AffectedImport: &model.ImportInfo{Name: "app/bar", NameShort: "bar", Line: 3},
+		AffectedFile:   "app/baz/baz.go",
+	}}
+	return &model.Analysis{
+		Cycles: []*model.Pkg{baz, bar, newDiagramPkg("foo", "app")},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{
+			{"bar", "baz", "bar"},
+			{"baz", "bar", "baz"},
+		}},
+	}
+}
+
+func TestToCheckstyle(t *testing.T) {
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="app/bar/bar.go">
+    <error line="5" severity="error" message="import &#34;app/baz&#34; creates a cycle: bar -&gt; baz -&gt; bar" source="anticycle.ImportCycle"></error>
+  </file>
+  <file name="app/baz/baz.go">
+    <error line="3" severity="error" message="import &#34;app/bar&#34; creates a cycle: baz -&gt; bar -&gt; baz" source="anticycle.ImportCycle"></error>
+  </file>
+</checkstyle>`
+
+	result, err := ToCheckstyle(newAnnotationsAnalysis())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestToCheckstyle_WithEmptyInput(t *testing.T) {
+	analysis := &model.Analysis{
+		Cycles:   []*model.Pkg{},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{}},
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3"></checkstyle>`
+
+	result, err := ToCheckstyle(analysis)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestToGitHub(t *testing.T) {
+	expected := `::error file=app/bar/bar.go,line=5,title=Import cycle::import "app/baz" creates a cycle: bar -> baz -> bar
+::error file=app/baz/baz.go,line=3,title=Import cycle::import "app/bar" creates a cycle: baz -> bar -> baz`
+
+	result, err := ToGitHub(newAnnotationsAnalysis())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestToGitHub_EscapesSpecialCharacters(t *testing.T) {
+	assert.Equal(t, "100%25%0Adone", escapeGitHubData("100%\ndone"))
+	assert.Equal(t, "a%2Cb%3Ac", escapeGitHubProperty("a,b:c"))
+}
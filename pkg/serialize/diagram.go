@@ -0,0 +1,180 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/anticycle/anticycle/internal/pkg/scan"
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// diagram is a graph of analyzed packages grouped by directories.
+type diagram struct {
+	nodes  []*model.Pkg
+	groups []string
+	edges  []diagramEdge
+	index  *scan.Index
+}
+
+type diagramEdge struct {
+	from, to int
+	cycle    bool
+}
+
+// edgeFile is a file of importing package with import which creates an edge.
+type edgeFile struct {
+	path, imp string
+	line      int
+}
+
+// newDiagram builds graph of packages sorted by path. Edges between
+// packages of the same strongly connected component are marked as cycle edges.
+func newDiagram(analysis *model.Analysis) *diagram {
+	d := &diagram{
+		nodes:  make([]*model.Pkg, len(analysis.Cycles)),
+		groups: make([]string, 0),
+		edges:  make([]diagramEdge, 0),
+	}
+	copy(d.nodes, analysis.Cycles)
+	sort.SliceStable(d.nodes, func(i, j int) bool {
+		if d.nodes[i].Path != d.nodes[j].Path {
+			return d.nodes[i].Path < d.nodes[j].Path
+		}
+		return d.nodes[i].Name < d.nodes[j].Name
+	})
+
+	for _, pkg := range d.nodes {
+		if group := path.Dir(pkg.Path); !model.SliceContains(d.groups, group) {
+			d.groups = append(d.groups, group)
+		}
+	}
+
+	d.index = scan.NewIndex(d.nodes)
+	deps := scan.Dependencies(d.nodes)
+	component := make([]int, len(d.nodes))
+	for idx, scc := range scan.StronglyConnected(scan.CycleDependencies(d.nodes)) {
+		for _, node := range scc {
+			component[node] = idx
+			if len(scc) == 1 {
+				component[node] = -1
+			}
+		}
+	}
+	for from, imports := range deps {
+		for _, to := range imports {
+			d.edges = append(d.edges, diagramEdge{
+				from:  from,
+				to:    to,
+				cycle: component[from] >= 0 && component[from] == component[to],
+			})
+		}
+	}
+	return d
+}
+
+// nodesIn returns indexes of nodes which belong to directory group.
+func (d *diagram) nodesIn(group string) []int {
+	nodes := make([]int, 0)
+	for idx, pkg := range d.nodes {
+		if path.Dir(pkg.Path) == group {
+			nodes = append(nodes, idx)
+		}
+	}
+	return nodes
+}
+
+// edgeFiles returns files of importing package which create the edge.
+func (d *diagram) edgeFiles(edge diagramEdge) []edgeFile {
+	files := make([]edgeFile, 0)
+	for _, file := range d.nodes[edge.from].Files {
+		for _, imp := range file.Imports {
+			if d.index.Find(imp) == edge.to {
+				files = append(files, edgeFile{path: file.Path, imp: imp.Name, line: imp.Line})
+			}
+		}
+	}
+	return files
+}
+
+func (d *diagram) inCycle(node int) bool {
+	for _, edge := range d.edges {
+		if edge.cycle && (edge.from == node || edge.to == node) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToMermaid takes cycle analysis and produces Mermaid flowchart.
+// Packages are grouped by directories, and cycles are highlighted.
+func ToMermaid(analysis *model.Analysis) (string, error) {
+	d := newDiagram(analysis)
+
+	var output strings.Builder
+	output.WriteString("graph LR\n")
+	for gIdx, group := range d.groups {
+		output.WriteString(fmt.Sprintf("  subgraph g%d [\"%s\"]\n", gIdx, group))
+		for _, node := range d.nodesIn(group) {
+			output.WriteString(fmt.Sprintf("    n%d[\"%s\"]\n", node, d.nodes[node].Name))
+		}
+		output.WriteString("  end\n")
+	}
+
+	cycleEdges := make([]string, 0)
+	for eIdx, edge := range d.edges {
+		output.WriteString(fmt.Sprintf("  n%d --> n%d\n", edge.from, edge.to))
+		if edge.cycle {
+			cycleEdges = append(cycleEdges, fmt.Sprint(eIdx))
+		}
+	}
+
+	cycleNodes := make([]string, 0)
+	for node := range d.nodes {
+		if d.inCycle(node) {
+			cycleNodes = append(cycleNodes, fmt.Sprintf("n%d", node))
+		}
+	}
+	if len(cycleNodes) > 0 {
+		output.WriteString("  classDef cycle fill:#fdd,stroke:#d00\n")
+		output.WriteString(fmt.Sprintf("  class %s cycle\n", strings.Join(cycleNodes, ",")))
+		output.WriteString(fmt.Sprintf("  linkStyle %s stroke:#d00,stroke-width:2px\n", strings.Join(cycleEdges, ",")))
+	}
+
+	return strings.TrimRight(output.String(), "\n"), nil
+}
+
+// ToPlantUML takes cycle analysis and produces PlantUML component diagram.
+// Packages are grouped by directories, and cycles are highlighted.
+func ToPlantUML(analysis *model.Analysis) (string, error) {
+	d := newDiagram(analysis)
+
+	var output strings.Builder
+	output.WriteString("@startuml\n")
+	for _, group := range d.groups {
+		output.WriteString(fmt.Sprintf("package \"%s\" {\n", group))
+		for _, node := range d.nodesIn(group) {
+			color := ""
+			if d.inCycle(node) {
+				color = " #FFDDDD"
+			}
+			output.WriteString(fmt.Sprintf("  [%s] as n%d%s\n", d.nodes[node].Name, node, color))
+		}
+		output.WriteString("}\n")
+	}
+	for _, edge := range d.edges {
+		arrow := "-->"
+		if edge.cycle {
+			arrow = "-[#DD0000,bold]->"
+		}
+		output.WriteString(fmt.Sprintf("n%d %s n%d\n", edge.from, arrow, edge.to))
+	}
+	output.WriteString("@enduml")
+
+	return output.String(), nil
+}
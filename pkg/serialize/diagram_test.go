@@ -0,0 +1,84 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"path"
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDiagramPkg(name, dir string, imports ...string) *model.Pkg {
+	pkg := model.NewPkg()
+	pkg.Name = name
+	pkg.Path = dir + "/" + name
+	for _, imp := range imports {
+		pkg.Imports[imp] = &model.ImportInfo{Name: imp, NameShort: path.Base(imp)}
+	}
+	return pkg
+}
+
+func newDiagramAnalysis() *model.Analysis {
+	return &model.Analysis{
+		Cycles: []*model.Pkg{
+			newDiagramPkg("foo", "app/lib"),
+			newDiagramPkg("baz", "app", "app/bar", "app/lib/foo"),
+			newDiagramPkg("bar", "app", "app/baz"),
+		},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{}},
+	}
+}
+
+func TestToMermaid(t *testing.T) {
+	expected := `graph LR
+  subgraph g0 ["app"]
+    n0["bar"]
+    n1["baz"]
+  end
+  subgraph g1 ["app/lib"]
+    n2["foo"]
+  end
+  n0 --> n1
+  n1 --> n0
+  n1 --> n2
+  classDef cycle fill:#fdd,stroke:#d00
+  class n0,n1 cycle
+  linkStyle 0,1 stroke:#d00,stroke-width:2px`
+
+	result, err := ToMermaid(newDiagramAnalysis())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestToMermaid_WithEmptyInput(t *testing.T) {
+	analysis := &model.Analysis{
+		Cycles:   []*model.Pkg{},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{}},
+	}
+	result, err := ToMermaid(analysis)
+	assert.NoError(t, err)
+	assert.Equal(t, "graph LR", result)
+}
+
+func TestToPlantUML(t *testing.T) {
+	expected := `@startuml
+package "app" {
+  [bar] as n0 #FFDDDD
+  [baz] as n1 #FFDDDD
+}
+package "app/lib" {
+  [foo] as n2
+}
+n0 -[#DD0000,bold]-> n1
+n1 -[#DD0000,bold]-> n0
+n1 --> n2
+@enduml`
+
+	result, err := ToPlantUML(newDiagramAnalysis())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
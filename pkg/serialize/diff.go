@@ -0,0 +1,122 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// DiffToJSON takes differences of two analyses and produces JSON string.
+func DiffToJSON(report *model.DiffReport) (string, error) {
+	jsonBytes, err := json.Marshal(report)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
+
+// DiffToTxt takes differences of two analyses and produces human friendly text output.
+// Sections without differences are skipped.
+func DiffToTxt(report *model.DiffReport) (string, error) {
+	sections := make([]string, 0)
+	if len(report.Added) > 0 {
+		sections = append(sections, diffSection("Added cycles", cyclesToLines(report.Added, " -> ")))
+	}
+	if len(report.Removed) > 0 {
+		sections = append(sections, diffSection("Removed cycles", cyclesToLines(report.Removed, " -> ")))
+	}
+	if len(report.Changed) > 0 {
+		lines := make([]string, 0, len(report.Changed))
+		for _, change := range report.Changed {
+			lines = append(lines, fmt.Sprintf("%s\n   => %s",
+				strings.Join(change.Old, " -> "), strings.Join(change.New, " -> ")))
+		}
+		sections = append(sections, diffSection("Changed cycles", lines))
+	}
+	if len(report.EnteredCycles) > 0 {
+		sections = append(sections, diffSection("Packages which entered cycles", report.EnteredCycles))
+	}
+	if len(report.LeftCycles) > 0 {
+		sections = append(sections, diffSection("Packages which left cycles", report.LeftCycles))
+	}
+	if len(report.NewEdges) > 0 {
+		sections = append(sections, diffSection("New edges", edgesToLines(report.NewEdges, "%s -> %s")))
+	}
+	if len(sections) == 0 {
+		return "No differences found", nil
+	}
+	return strings.Join(sections, "\n\n"), nil
+}
+
+// DiffToMarkdown takes differences of two analyses and produces Markdown report
+// with summary table followed by a list for each kind of difference.
+func DiffToMarkdown(report *model.DiffReport) (string, error) {
+	var output strings.Builder
+	output.WriteString("## Anticycle diff\n\n")
+	output.WriteString("| Added | Removed | Changed | Entered cycles | Left cycles | New edges |\n")
+	output.WriteString("| ---: | ---: | ---: | ---: | ---: | ---: |\n")
+	output.WriteString(fmt.Sprintf("| %d | %d | %d | %d | %d | %d |\n",
+		len(report.Added), len(report.Removed), len(report.Changed),
+		len(report.EnteredCycles), len(report.LeftCycles), len(report.NewEdges)))
+
+	changed := make([]string, 0, len(report.Changed))
+	for _, change := range report.Changed {
+		changed = append(changed, fmt.Sprintf("%s ⇒ %s",
+			strings.Join(change.Old, " → "), strings.Join(change.New, " → ")))
+	}
+	lists := []struct {
+		title string
+		items []string
+	}{
+		{"Added cycles", cyclesToLines(report.Added, " → ")},
+		{"Removed cycles", cyclesToLines(report.Removed, " → ")},
+		{"Changed cycles", changed},
+		{"Packages which entered cycles", codeItems(report.EnteredCycles)},
+		{"Packages which left cycles", codeItems(report.LeftCycles)},
+		{"New edges", edgesToLines(report.NewEdges, "`%s` → `%s`")},
+	}
+	for _, list := range lists {
+		if len(list.items) == 0 {
+			continue
+		}
+		output.WriteString(fmt.Sprintf("\n### %s\n\n", list.title))
+		for _, item := range list.items {
+			output.WriteString(fmt.Sprintf("- %s\n", item))
+		}
+	}
+	return strings.TrimRight(output.String(), "\n"), nil
+}
+
+func diffSection(title string, lines []string) string {
+	return fmt.Sprintf("%s (%d)\n\n%s", title, len(lines), strings.Join(lines, "\n"))
+}
+
+func cyclesToLines(cycles [][]string, sep string) []string {
+	lines := make([]string, 0, len(cycles))
+	for _, cycle := range cycles {
+		lines = append(lines, strings.Join(cycle, sep))
+	}
+	return lines
+}
+
+func edgesToLines(edges []*model.Edge, format string) []string {
+	lines := make([]string, 0, len(edges))
+	for _, edge := range edges {
+		lines = append(lines, fmt.Sprintf(format, edge.From, edge.To))
+	}
+	return lines
+}
+
+func codeItems(items []string) []string {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, "`"+item+"`")
+	}
+	return lines
+}
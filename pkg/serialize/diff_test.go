@@ -0,0 +1,92 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDiffReport() *model.DiffReport {
+	return &model.DiffReport{
+		Added:   [][]string{{"pas", "run", "pas"}},
+		Removed: [][]string{{"lib", "util", "lib"}},
+		Changed: []*model.CycleChange{{
+			Old: []string{"bar", "baz", "bar"},
+			New: []string{"bar", "foo", "baz", "bar"},
+		}},
+		EnteredCycles: []string{"app/foo"},
+		LeftCycles:    []string{"app/lib", "app/util"},
+		NewEdges:      []*model.Edge{{From: "app/bar", To: "app/foo"}},
+	}
+}
+
+func TestDiffToTxt(t *testing.T) {
+	expected := `Added cycles (1)
+
+pas -> run -> pas
+
+Removed cycles (1)
+
+lib -> util -> lib
+
+Changed cycles (1)
+
+bar -> baz -> bar
+   => bar -> foo -> baz -> bar
+
+Packages which entered cycles (1)
+
+app/foo
+
+Packages which left cycles (2)
+
+app/lib
+app/util
+
+New edges (1)
+
+app/bar -> app/foo`
+
+	result, err := DiffToTxt(newDiffReport())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestDiffToTxt_WithoutDifferences(t *testing.T) {
+	result, err := DiffToTxt(&model.DiffReport{})
+	assert.NoError(t, err)
+	assert.Equal(t, "No differences found", result)
+}
+
+func TestDiffToJSON(t *testing.T) {
+	expected := `{"added":[["pas","run","pas"]],"removed":[["lib","util","lib"]],` +
+		`"changed":[{"old":["bar","baz","bar"],"new":["bar","foo","baz","bar"]}],` +
+		`"enteredCycles":["app/foo"],"leftCycles":["app/lib","app/util"],` +
+		`"newEdges":[{"from":"app/bar","to":"app/foo"}]}`
+
+	result, err := DiffToJSON(newDiffReport())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestDiffToMarkdown(t *testing.T) {
+	expected := "## Anticycle diff\n\n" +
+		"| Added | Removed | Changed | Entered cycles | Left cycles | New edges |\n" +
+		"| ---: | ---: | ---: | ---: | ---: | ---: |\n" +
+		"| 1 | 1 | 1 | 1 | 2 | 1 |\n\n" +
+		"### Added cycles\n\n- pas → run → pas\n\n" +
+		"### Removed cycles\n\n- lib → util → lib\n\n" +
+		"### Changed cycles\n\n- bar → baz → bar ⇒ bar → foo → baz → bar\n\n" +
+		"### Packages which entered cycles\n\n- `app/foo`\n\n" +
+		"### Packages which left cycles\n\n- `app/lib`\n- `app/util`\n\n" +
+		"### New edges\n\n- `app/bar` → `app/foo`"
+
+	result, err := DiffToMarkdown(newDiffReport())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
@@ -0,0 +1,245 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// ToCSV takes cycle analysis and produces edge list, one import between
+// packages per row. Weight is a number of imports which create the edge
+// and files is a semicolon-separated list of importing files.
+func ToCSV(analysis *model.Analysis) (string, error) {
+	d := newDiagram(analysis)
+
+	var output strings.Builder
+	writer := csv.NewWriter(&output)
+	records := [][]string{{"from", "to", "weight", "inCycle", "files"}}
+	for _, edge := range d.edges {
+		files := d.edgeFiles(edge)
+		paths := make([]string, 0, len(files))
+		for _, file := range files {
+			if !model.SliceContains(paths, file.path) {
+				paths = append(paths, file.path)
+			}
+		}
+		records = append(records, []string{
+			d.nodes[edge.from].Path,
+			d.nodes[edge.to].Path,
+			fmt.Sprint(len(files)),
+			fmt.Sprint(edge.cycle),
+			strings.Join(paths, ";"),
+		})
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(output.String(), "\n"), nil
+}
+
+// graphAttr is an attribute of a node or an edge shared by GraphML and GEXF.
+type graphAttr struct {
+	id, name, kind string
+	value          func(d *diagram, idx int) string
+}
+
+var nodeAttrs = []graphAttr{
+	{"name", "name", "string", func(d *diagram, idx int) string { return d.nodes[idx].Name }},
+	{"path", "path", "string", func(d *diagram, idx int) string { return d.nodes[idx].Path }},
+	{"module", "module", "string", func(d *diagram, idx int) string { return d.nodes[idx].Module }},
+	{"files", "files", "int", func(d *diagram, idx int) string { return fmt.Sprint(len(d.nodes[idx].Files)) }},
+	{"cycle", "inCycle", "boolean", func(d *diagram, idx int) string { return fmt.Sprint(d.inCycle(idx)) }},
+}
+
+var edgeAttrs = []graphAttr{
+	{"weight", "weight", "int", func(d *diagram, idx int) string { return fmt.Sprint(len(d.edgeFiles(d.edges[idx]))) }},
+	{"ecycle", "inCycle", "boolean", func(d *diagram, idx int) string { return fmt.Sprint(d.edges[idx].cycle) }},
+}
+
+type (
+	graphML struct {
+		XMLName xml.Name     `xml:"graphml"`
+		XMLNS   string       `xml:"xmlns,attr"`
+		Keys    []graphMLKey `xml:"key"`
+		Graph   graphMLGraph `xml:"graph"`
+	}
+
+	graphMLKey struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+
+	graphMLGraph struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	}
+
+	graphMLNode struct {
+		ID   string        `xml:"id,attr"`
+		Data []graphMLData `xml:"data"`
+	}
+
+	graphMLEdge struct {
+		Source string        `xml:"source,attr"`
+		Target string        `xml:"target,attr"`
+		Data   []graphMLData `xml:"data"`
+	}
+
+	graphMLData struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+// ToGraphML takes cycle analysis and produces directed graph of packages
+// in GraphML format, with path, module, number of files and cycle membership
+// of each package.
+func ToGraphML(analysis *model.Analysis) (string, error) {
+	d := newDiagram(analysis)
+	graph := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys:  make([]graphMLKey, 0, len(nodeAttrs)+len(edgeAttrs)),
+		Graph: graphMLGraph{
+			ID:          "anticycle",
+			EdgeDefault: "directed",
+			Nodes:       make([]graphMLNode, 0, len(d.nodes)),
+			Edges:       make([]graphMLEdge, 0, len(d.edges)),
+		},
+	}
+	for _, attr := range nodeAttrs {
+		graph.Keys = append(graph.Keys, graphMLKey{ID: attr.id, For: "node", Name: attr.name, Type: attr.kind})
+	}
+	for _, attr := range edgeAttrs {
+		graph.Keys = append(graph.Keys, graphMLKey{ID: attr.id, For: "edge", Name: attr.name, Type: attr.kind})
+	}
+
+	for idx := range d.nodes {
+		node := graphMLNode{ID: fmt.Sprintf("n%d", idx)}
+		for _, attr := range nodeAttrs {
+			node.Data = append(node.Data, graphMLData{Key: attr.id, Value: attr.value(d, idx)})
+		}
+		graph.Graph.Nodes = append(graph.Graph.Nodes, node)
+	}
+	for idx, edge := range d.edges {
+		graphEdge := graphMLEdge{
+			Source: fmt.Sprintf("n%d", edge.from),
+			Target: fmt.Sprintf("n%d", edge.to),
+		}
+		for _, attr := range edgeAttrs {
+			graphEdge.Data = append(graphEdge.Data, graphMLData{Key: attr.id, Value: attr.value(d, idx)})
+		}
+		graph.Graph.Edges = append(graph.Graph.Edges, graphEdge)
+	}
+
+	return marshalXML(graph)
+}
+
+type (
+	gexf struct {
+		XMLName xml.Name  `xml:"gexf"`
+		XMLNS   string    `xml:"xmlns,attr"`
+		Version string    `xml:"version,attr"`
+		Graph   gexfGraph `xml:"graph"`
+	}
+
+	gexfGraph struct {
+		DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+		Attributes      []gexfAttributes `xml:"attributes"`
+		Nodes           []gexfNode       `xml:"nodes>node"`
+		Edges           []gexfEdge       `xml:"edges>edge"`
+	}
+
+	gexfAttributes struct {
+		Class      string          `xml:"class,attr"`
+		Attributes []gexfAttribute `xml:"attribute"`
+	}
+
+	gexfAttribute struct {
+		ID    string `xml:"id,attr"`
+		Title string `xml:"title,attr"`
+		Type  string `xml:"type,attr"`
+	}
+
+	gexfNode struct {
+		ID     string          `xml:"id,attr"`
+		Label  string          `xml:"label,attr"`
+		Values []gexfAttrValue `xml:"attvalues>attvalue"`
+	}
+
+	gexfEdge struct {
+		ID     string          `xml:"id,attr"`
+		Source string          `xml:"source,attr"`
+		Target string          `xml:"target,attr"`
+		Weight string          `xml:"weight,attr"`
+		Values []gexfAttrValue `xml:"attvalues>attvalue"`
+	}
+
+	gexfAttrValue struct {
+		For   string `xml:"for,attr"`
+		Value string `xml:"value,attr"`
+	}
+)
+
+// ToGEXF takes cycle analysis and produces directed graph of packages
+// in GEXF format, ready to be opened in Gephi.
+func ToGEXF(analysis *model.Analysis) (string, error) {
+	d := newDiagram(analysis)
+	graph := gexf{
+		XMLNS:   "http://www.gexf.net/1.2draft",
+		Version: "1.2",
+		Graph: gexfGraph{
+			DefaultEdgeType: "directed",
+			Attributes: []gexfAttributes{
+				{Class: "node", Attributes: gexfAttributesOf(nodeAttrs)},
+				{Class: "edge", Attributes: gexfAttributesOf(edgeAttrs)},
+			},
+			Nodes: make([]gexfNode, 0, len(d.nodes)),
+			Edges: make([]gexfEdge, 0, len(d.edges)),
+		},
+	}
+
+	for idx, pkg := range d.nodes {
+		node := gexfNode{ID: fmt.Sprintf("n%d", idx), Label: pkg.Name}
+		for _, attr := range nodeAttrs {
+			node.Values = append(node.Values, gexfAttrValue{For: attr.id, Value: attr.value(d, idx)})
+		}
+		graph.Graph.Nodes = append(graph.Graph.Nodes, node)
+	}
+	for idx, edge := range d.edges {
+		graphEdge := gexfEdge{
+			ID:     fmt.Sprintf("e%d", idx),
+			Source: fmt.Sprintf("n%d", edge.from),
+			Target: fmt.Sprintf("n%d", edge.to),
+			Weight: fmt.Sprint(len(d.edgeFiles(edge))),
+		}
+		for _, attr := range edgeAttrs {
+			graphEdge.Values = append(graphEdge.Values, gexfAttrValue{For: attr.id, Value: attr.value(d, idx)})
+		}
+		graph.Graph.Edges = append(graph.Graph.Edges, graphEdge)
+	}
+
+	return marshalXML(graph)
+}
+
+func gexfAttributesOf(attrs []graphAttr) []gexfAttribute {
+	result := make([]gexfAttribute, 0, len(attrs))
+	for _, attr := range attrs {
+		kind := attr.kind
+		if kind == "int" {
+			kind = "integer"
+		}
+		result = append(result, gexfAttribute{ID: attr.id, Title: attr.name, Type: kind})
+	}
+	return result
+}
@@ -0,0 +1,163 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newGraphAnalysis() *model.Analysis {
+	analysis := newDiagramAnalysis()
+	baz := analysis.Cycles[1]
+	baz.Module = "app"
+	baz.Files = []*model.File{
+		{Path: "app/baz/a.go", Imports: []*model.ImportInfo{
+			{Name: "app/bar", NameShort: "bar", Line: 3},
+			{Name: "app/lib/foo", NameShort: "foo", Line: 4},
+		}},
+		{Path: "app/baz/b.go", Imports: []*model.ImportInfo{
+			{Name: "app/bar", NameShort: "bar", Line: 3},
+		}},
+	}
+	return analysis
+}
+
+func TestToCSV(t *testing.T) {
+	expected := `from,to,weight,inCycle,files
+app/bar,app/baz,0,true,
+app/baz,app/bar,2,true,app/baz/a.go;app/baz/b.go
+app/baz,app/lib/foo,1,false,app/baz/a.go`
+
+	result, err := ToCSV(newGraphAnalysis())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestToGraphML(t *testing.T) {
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="name" for="node" attr.name="name" attr.type="string"></key>
+  <key id="path" for="node" attr.name="path" attr.type="string"></key>
+  <key id="module" for="node" attr.name="module" attr.type="string"></key>
+  <key id="files" for="node" attr.name="files" attr.type="int"></key>
+  <key id="cycle" for="node" attr.name="inCycle" attr.type="boolean"></key>
+  <key id="weight" for="edge" attr.name="weight" attr.type="int"></key>
+  <key id="ecycle" for="edge" attr.name="inCycle" attr.type="boolean"></key>
+  <graph id="anticycle" edgedefault="directed">
+    <node id="n0">
+      <data key="name">bar</data>
+      <data key="path">app/bar</data>
+      <data key="module"></data>
+      <data key="files">0</data>
+      <data key="cycle">true</data>
+    </node>
+    <node id="n1">
+      <data key="name">baz</data>
+      <data key="path">app/baz</data>
+      <data key="module">app</data>
+      <data key="files">2</data>
+      <data key="cycle">true</data>
+    </node>
+    <node id="n2">
+      <data key="name">foo</data>
+      <data key="path">app/lib/foo</data>
+      <data key="module"></data>
+      <data key="files">0</data>
+      <data key="cycle">false</data>
+    </node>
+    <edge source="n0" target="n1">
+      <data key="weight">0</data>
+      <data key="ecycle">true</data>
+    </edge>
+    <edge source="n1" target="n0">
+      <data key="weight">2</data>
+      <data key="ecycle">true</data>
+    </edge>
+    <edge source="n1" target="n2">
+      <data key="weight">1</data>
+      <data key="ecycle">false</data>
+    </edge>
+  </graph>
+</graphml>`
+
+	result, err := ToGraphML(newGraphAnalysis())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestToGEXF(t *testing.T) {
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2">
+  <graph defaultedgetype="directed">
+    <attributes class="node">
+      <attribute id="name" title="name" type="string"></attribute>
+      <attribute id="path" title="path" type="string"></attribute>
+      <attribute id="module" title="module" type="string"></attribute>
+      <attribute id="files" title="files" type="integer"></attribute>
+      <attribute id="cycle" title="inCycle" type="boolean"></attribute>
+    </attributes>
+    <attributes class="edge">
+      <attribute id="weight" title="weight" type="integer"></attribute>
+      <attribute id="ecycle" title="inCycle" type="boolean"></attribute>
+    </attributes>
+    <nodes>
+      <node id="n0" label="bar">
+        <attvalues>
+          <attvalue for="name" value="bar"></attvalue>
+          <attvalue for="path" value="app/bar"></attvalue>
+          <attvalue for="module" value=""></attvalue>
+          <attvalue for="files" value="0"></attvalue>
+          <attvalue for="cycle" value="true"></attvalue>
+        </attvalues>
+      </node>
+      <node id="n1" label="baz">
+        <attvalues>
+          <attvalue for="name" value="baz"></attvalue>
+          <attvalue for="path" value="app/baz"></attvalue>
+          <attvalue for="module" value="app"></attvalue>
+          <attvalue for="files" value="2"></attvalue>
+          <attvalue for="cycle" value="true"></attvalue>
+        </attvalues>
+      </node>
+      <node id="n2" label="foo">
+        <attvalues>
+          <attvalue for="name" value="foo"></attvalue>
+          <attvalue for="path" value="app/lib/foo"></attvalue>
+          <attvalue for="module" value=""></attvalue>
+          <attvalue for="files" value="0"></attvalue>
+          <attvalue for="cycle" value="false"></attvalue>
+        </attvalues>
+      </node>
+    </nodes>
+    <edges>
+      <edge id="e0" source="n0" target="n1" weight="0">
+        <attvalues>
+          <attvalue for="weight" value="0"></attvalue>
+          <attvalue for="ecycle" value="true"></attvalue>
+        </attvalues>
+      </edge>
+      <edge id="e1" source="n1" target="n0" weight="2">
+        <attvalues>
+          <attvalue for="weight" value="2"></attvalue>
+          <attvalue for="ecycle" value="true"></attvalue>
+        </attvalues>
+      </edge>
+      <edge id="e2" source="n1" target="n2" weight="1">
+        <attvalues>
+          <attvalue for="weight" value="1"></attvalue>
+          <attvalue for="ecycle" value="false"></attvalue>
+        </attvalues>
+      </edge>
+    </edges>
+  </graph>
+</gexf>`
+
+	result, err := ToGEXF(newGraphAnalysis())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
@@ -0,0 +1,320 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"html/template"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+type (
+	htmlReport struct {
+		Nodes  []*htmlNode `json:"nodes"`
+		Edges  []*htmlEdge `json:"edges"`
+		Cycles [][]string  `json:"cycles"`
+		Groups []string    `json:"groups"`
+	}
+
+	htmlNode struct {
+		Name  string `json:"name"`
+		Path  string `json:"path"`
+		Group int    `json:"group"`
+		Cycle bool   `json:"cycle"`
+	}
+
+	htmlEdge struct {
+		From  int         `json:"from"`
+		To    int         `json:"to"`
+		Cycle bool        `json:"cycle"`
+		Files []*htmlFile `json:"files"`
+	}
+
+	htmlFile struct {
+		Path   string `json:"path"`
+		Import string `json:"import"`
+		Line   int    `json:"line"`
+	}
+)
+
+// ToHTML takes cycle analysis and produces single, self-contained HTML page
+// with interactive graph of packages, list of cycles and files affected by each import.
+// The page does not load any external resources, so it works offline.
+func ToHTML(analysis *model.Analysis) (string, error) {
+	d := newDiagram(analysis)
+	report := &htmlReport{
+		Nodes:  make([]*htmlNode, 0, len(d.nodes)),
+		Edges:  make([]*htmlEdge, 0, len(d.edges)),
+		Cycles: analysis.Metadata.Cycles,
+		Groups: d.groups,
+	}
+	if report.Cycles == nil {
+		report.Cycles = make([][]string, 0)
+	}
+
+	for gIdx, group := range d.groups {
+		for _, node := range d.nodesIn(group) {
+			for len(report.Nodes) <= node {
+				report.Nodes = append(report.Nodes, nil)
+			}
+			report.Nodes[node] = &htmlNode{
+				Name:  d.nodes[node].Name,
+				Path:  d.nodes[node].Path,
+				Group: gIdx,
+				Cycle: d.inCycle(node),
+			}
+		}
+	}
+
+	for _, edge := range d.edges {
+		htmlEdge := &htmlEdge{
+			From:  edge.from,
+			To:    edge.to,
+			Cycle: edge.cycle,
+			Files: make([]*htmlFile, 0),
+		}
+		for _, file := range d.edgeFiles(edge) {
+			htmlEdge.Files = append(htmlEdge.Files, &htmlFile{
+				Path:   file.path,
+				Import: file.imp,
+				Line:   file.line,
+			})
+		}
+		report.Edges = append(report.Edges, htmlEdge)
+	}
+
+	var output strings.Builder
+	if err := htmlTemplate.Execute(&output, report); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(output.String(), "\n"), nil
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Anticycle report</title>
+<style>
+  * { box-sizing: border-box; }
+  body { margin: 0; font: 14px/1.4 sans-serif; color: #222; display: flex; height: 100vh; }
+  #side { width: 340px; overflow: auto; border-right: 1px solid #ccc; padding: 12px; }
+  #graph { flex: 1; position: relative; }
+  svg { width: 100%; height: 100%; cursor: grab; background: #fafafa; }
+  h1 { font-size: 18px; margin: 0 0 8px; }
+  h2 { font-size: 15px; margin: 16px 0 6px; }
+  ul { list-style: none; margin: 0; padding: 0; }
+  li.cycle { padding: 4px 6px; cursor: pointer; border-radius: 3px; }
+  li.cycle:hover, li.cycle.active { background: #fdd; }
+  li.file { font-family: monospace; font-size: 12px; padding: 2px 0; word-break: break-all; }
+  .hint { color: #777; font-size: 12px; }
+  .node circle { fill: #cde; stroke: #579; stroke-width: 1.5px; }
+  .node.cycle circle { fill: #fdd; stroke: #d00; }
+  .node.dim, .edge.dim { opacity: 0.15; }
+  .node text { font-size: 12px; pointer-events: none; }
+  .edge { stroke: #999; stroke-width: 1.5px; cursor: pointer; }
+  .edge.cycle { stroke: #d00; stroke-width: 2.5px; }
+  .edge.active { stroke: #06c; stroke-width: 4px; }
+</style>
+</head>
+<body>
+<div id="side">
+  <h1>Anticycle report</h1>
+  <div class="hint">Scroll to zoom, drag background to pan, drag nodes to move them.</div>
+  <h2 id="cycles-title"></h2>
+  <ul id="cycles"></ul>
+  <h2>Import details</h2>
+  <div id="details" class="hint">Click an edge to see affected files.</div>
+</div>
+<div id="graph">
+  <svg id="svg">
+    <defs>
+      <marker id="arrow" viewBox="0 0 10 10" refX="22" refY="5" markerWidth="6" markerHeight="6" orient="auto">
+        <path d="M0,0 L10,5 L0,10 z" fill="#999"></path>
+      </marker>
+      <marker id="arrow-cycle" viewBox="0 0 10 10" refX="18" refY="5" markerWidth="6" markerHeight="6" orient="auto">
+        <path d="M0,0 L10,5 L0,10 z" fill="#d00"></path>
+      </marker>
+    </defs>
+    <g id="viewport"></g>
+  </svg>
+</div>
+<script>
+(function () {
+  "use strict";
+  var data = {{.}};
+  var NS = "http://www.w3.org/2000/svg";
+  var svg = document.getElementById("svg");
+  var viewport = document.getElementById("viewport");
+  var view = {x: 0, y: 0, k: 1};
+
+  function el(name, attrs, parent) {
+    var e = document.createElementNS(NS, name);
+    for (var a in attrs) { e.setAttribute(a, attrs[a]); }
+    if (parent) { parent.appendChild(e); }
+    return e;
+  }
+
+  function html(name, text, cls, parent) {
+    var e = document.createElement(name);
+    e.textContent = text;
+    if (cls) { e.className = cls; }
+    if (parent) { parent.appendChild(e); }
+    return e;
+  }
+
+  var width = svg.clientWidth || 800, height = svg.clientHeight || 600;
+  data.nodes.forEach(function (n, i) {
+    var angle = 2 * Math.PI * i / Math.max(data.nodes.length, 1);
+    n.x = width / 2 + Math.cos(angle) * width / 4;
+    n.y = height / 2 + Math.sin(angle) * height / 4;
+    n.vx = 0; n.vy = 0;
+  });
+
+  var edgeLayer = el("g", {}, viewport);
+  var nodeLayer = el("g", {}, viewport);
+  data.edges.forEach(function (e) {
+    e.el = el("line", {"class": "edge" + (e.cycle ? " cycle" : ""),
+      "marker-end": e.cycle ? "url(#arrow-cycle)" : "url(#arrow)"}, edgeLayer);
+    e.el.addEventListener("click", function () { showEdge(e); });
+  });
+  data.nodes.forEach(function (n) {
+    n.el = el("g", {"class": "node" + (n.cycle ? " cycle" : "")}, nodeLayer);
+    el("circle", {r: 8}, n.el);
+    var label = el("text", {x: 11, y: 4}, n.el);
+    label.textContent = n.name;
+    var title = el("title", {}, n.el);
+    title.textContent = n.path;
+    n.el.addEventListener("mousedown", function (ev) { ev.stopPropagation(); drag = {node: n}; });
+  });
+
+  function tick() {
+    var i, j, a, b, dx, dy, d, f;
+    for (i = 0; i < data.nodes.length; i++) {
+      a = data.nodes[i];
+      for (j = i + 1; j < data.nodes.length; j++) {
+        b = data.nodes[j];
+        dx = b.x - a.x; dy = b.y - a.y;
+        d = Math.max(Math.sqrt(dx * dx + dy * dy), 1);
+        f = 3000 / (d * d);
+        a.vx -= f * dx / d; a.vy -= f * dy / d;
+        b.vx += f * dx / d; b.vy += f * dy / d;
+      }
+    }
+    data.edges.forEach(function (e) {
+      a = data.nodes[e.from]; b = data.nodes[e.to];
+      dx = b.x - a.x; dy = b.y - a.y;
+      d = Math.max(Math.sqrt(dx * dx + dy * dy), 1);
+      f = (d - 120) * 0.02;
+      a.vx += f * dx / d; a.vy += f * dy / d;
+      b.vx -= f * dx / d; b.vy -= f * dy / d;
+    });
+    data.nodes.forEach(function (n) {
+      n.vx += (width / 2 - n.x) * 0.002;
+      n.vy += (height / 2 - n.y) * 0.002;
+      if (!drag || drag.node !== n) {
+        n.x += n.vx; n.y += n.vy;
+      }
+      n.vx *= 0.6; n.vy *= 0.6;
+    });
+  }
+
+  function draw() {
+    data.edges.forEach(function (e) {
+      var a = data.nodes[e.from], b = data.nodes[e.to];
+      e.el.setAttribute("x1", a.x); e.el.setAttribute("y1", a.y);
+      e.el.setAttribute("x2", b.x); e.el.setAttribute("y2", b.y);
+    });
+    data.nodes.forEach(function (n) {
+      n.el.setAttribute("transform", "translate(" + n.x + "," + n.y + ")");
+    });
+    viewport.setAttribute("transform", "translate(" + view.x + "," + view.y + ") scale(" + view.k + ")");
+  }
+
+  var ticks = 0;
+  function animate() {
+    tick(); draw();
+    if (++ticks < 400 || drag) { window.requestAnimationFrame(animate); }
+  }
+
+  var drag = null;
+  svg.addEventListener("mousedown", function (ev) { drag = {x: ev.clientX - view.x, y: ev.clientY - view.y}; });
+  window.addEventListener("mouseup", function () { drag = null; });
+  window.addEventListener("mousemove", function (ev) {
+    if (!drag) { return; }
+    if (drag.node) {
+      var r = svg.getBoundingClientRect();
+      drag.node.x = (ev.clientX - r.left - view.x) / view.k;
+      drag.node.y = (ev.clientY - r.top - view.y) / view.k;
+      ticks = Math.min(ticks, 300);
+      window.requestAnimationFrame(animate);
+    } else {
+      view.x = ev.clientX - drag.x; view.y = ev.clientY - drag.y;
+      draw();
+    }
+  });
+  svg.addEventListener("wheel", function (ev) {
+    ev.preventDefault();
+    var r = svg.getBoundingClientRect();
+    var mx = ev.clientX - r.left, my = ev.clientY - r.top;
+    var k = Math.min(Math.max(view.k * (ev.deltaY < 0 ? 1.1 : 0.9), 0.1), 10);
+    view.x = mx - (mx - view.x) * k / view.k;
+    view.y = my - (my - view.y) * k / view.k;
+    view.k = k;
+    draw();
+  });
+
+  function highlight(names) {
+    data.nodes.forEach(function (n) {
+      n.el.classList.toggle("dim", names !== null && names.indexOf(n.name) < 0);
+    });
+    data.edges.forEach(function (e) {
+      var from = data.nodes[e.from].name, to = data.nodes[e.to].name;
+      var inCycle = false;
+      if (names !== null) {
+        for (var i = 0; i + 1 < names.length; i++) {
+          if (names[i] === from && names[i + 1] === to) { inCycle = true; }
+        }
+      }
+      e.el.classList.toggle("dim", names !== null && !inCycle);
+    });
+  }
+
+  function showEdge(e) {
+    data.edges.forEach(function (other) { other.el.classList.toggle("active", other === e); });
+    var details = document.getElementById("details");
+    details.className = "";
+    details.textContent = "";
+    var from = data.nodes[e.from], to = data.nodes[e.to];
+    html("div", from.name + " -> " + to.name + (e.cycle ? " (cycle)" : ""), "", details);
+    var list = html("ul", "", "", details);
+    e.files.forEach(function (f) {
+      html("li", f.path + ":" + f.line + " \"" + f.import + "\"", "file", list);
+    });
+  }
+
+  var cycles = document.getElementById("cycles");
+  document.getElementById("cycles-title").textContent = "Found " + data.cycles.length + " cycles";
+  data.cycles.forEach(function (cycle) {
+    var item = html("li", cycle.join(" -> "), "cycle", cycles);
+    item.addEventListener("click", function () {
+      var active = item.classList.contains("active");
+      Array.prototype.forEach.call(cycles.children, function (c) { c.classList.remove("active"); });
+      if (active) {
+        highlight(null);
+      } else {
+        item.classList.add("active");
+        highlight(cycle);
+      }
+    });
+  });
+
+  animate();
+})();
+</script>
+</body>
+</html>
+`))
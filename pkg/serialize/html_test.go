@@ -0,0 +1,49 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToHTML(t *testing.T) {
+	analysis := newDiagramAnalysis()
+	baz := analysis.Cycles[1]
+	baz.Files = []*model.File{{
+		Path: "app/baz/baz.go",
+		Imports: []*model.ImportInfo{
+			{Name: "fmt", NameShort: "fmt", Kind: model.KindStdlib, Line: 3},
+			{Name: "app/bar", NameShort: "bar", Line: 4},
+		},
+	}}
+	analysis.Metadata.Cycles = [][]string{{"bar", "baz", "bar"}}
+
+	result, err := ToHTML(analysis)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(result, "<!DOCTYPE html>"))
+	assert.True(t, strings.HasSuffix(result, "</html>"))
+	assert.NotContains(t, result, "<script src")
+	assert.Contains(t, result, `"nodes":[{"name":"bar","path":"app/bar","group":0,"cycle":true},`+
+		`{"name":"baz","path":"app/baz","group":0,"cycle":true},`+
+		`{"name":"foo","path":"app/lib/foo","group":1,"cycle":false}]`)
+	assert.Contains(t, result, `{"from":1,"to":0,"cycle":true,"files":[{"path":"app/baz/baz.go","import":"app/bar","line":4}]}`)
+	assert.Contains(t, result, `"cycles":[["bar","baz","bar"]]`)
+}
+
+func TestToHTML_EscapesPackageNames(t *testing.T) {
+	analysis := &model.Analysis{
+		Cycles:   []*model.Pkg{newDiagramPkg("foo", "</script><b>")},
+		Metadata: &model.AnalysisMeta{},
+	}
+
+	result, err := ToHTML(analysis)
+	assert.NoError(t, err)
+	assert.NotContains(t, result, "</script><b>")
+	assert.Contains(t, result, `"cycles":[]`)
+}
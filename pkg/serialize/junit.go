@@ -0,0 +1,111 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+type (
+	junitSuites struct {
+		XMLName  xml.Name     `xml:"testsuites"`
+		Tests    int          `xml:"tests,attr"`
+		Failures int          `xml:"failures,attr"`
+		Suites   []junitSuite `xml:"testsuite"`
+	}
+
+	junitSuite struct {
+		Name     string      `xml:"name,attr"`
+		Tests    int         `xml:"tests,attr"`
+		Failures int         `xml:"failures,attr"`
+		Cases    []junitCase `xml:"testcase"`
+	}
+
+	junitCase struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		Failure   *junitFailure `xml:"failure,omitempty"`
+	}
+
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",cdata"`
+	}
+)
+
+// ToJUnit takes cycle analysis and produces JUnit XML report, where each package
+// is a test case. Package which is a part of a cycle fails with cycle paths
+// and affected files as a failure message, other packages pass.
+func ToJUnit(analysis *model.Analysis) (string, error) {
+	suite := junitSuite{
+		Name:  "anticycle",
+		Cases: make([]junitCase, 0, len(analysis.Cycles)),
+	}
+	for _, pkg := range analysis.Cycles {
+		testCase := junitCase{Name: pkg.Path, ClassName: pkg.Name}
+		if pkg.HaveCycle {
+			paths := pkgCyclePaths(analysis, pkg)
+			var text strings.Builder
+			for _, p := range paths {
+				text.WriteString(fmt.Sprintf("%s\n", p))
+			}
+			text.WriteString("\nAffected files:\n")
+			for _, c := range pkg.Cycles {
+				text.WriteString(fmt.Sprintf("%s imports %q\n", fileLocation(c), c.AffectedImport.Name))
+			}
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("import cycle: %s", strings.Join(paths, ", ")),
+				Type:    "ImportCycle",
+				Text:    text.String(),
+			}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Tests = len(suite.Cases)
+
+	report := junitSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitSuite{suite},
+	}
+	return marshalXML(report)
+}
+
+// pkgCyclePaths returns all cycles which start from the package, formatted as a path.
+func pkgCyclePaths(analysis *model.Analysis, pkg *model.Pkg) []string {
+	paths := make([]string, 0)
+	if analysis.Metadata == nil {
+		return paths
+	}
+	for _, cycle := range analysis.Metadata.Cycles {
+		if len(cycle) > 0 && cycle[0] == pkg.Name {
+			paths = append(paths, strings.Join(cycle, " -> "))
+		}
+	}
+	return paths
+}
+
+// fileLocation returns affected file with line of affected import, if known.
+func fileLocation(c *model.Cycle) string {
+	if c.AffectedImport.Line > 0 {
+		return fmt.Sprintf("%s:%d", c.AffectedFile, c.AffectedImport.Line)
+	}
+	return c.AffectedFile
+}
+
+// marshalXML produces indented XML document with a header.
+func marshalXML(v interface{}) (string, error) {
+	xmlBytes, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(xmlBytes), nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToJUnit(t *testing.T) {
+	bar := newDiagramPkg("bar", "app", "app/baz")
+	bar.HaveCycle = true
+	bar.Cycles = []*model.Cycle{{
+		AffectedImport: &model.ImportInfo{Name: "app/baz", NameShort: "baz", Line: 5},
+		AffectedFile:   "app/bar/bar.go",
+	}}
+	analysis := &model.Analysis{
+		Cycles: []*model.Pkg{bar, newDiagramPkg("foo", "app")},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{
+			{"bar", "baz", "bar"},
+			{"baz", "bar", "baz"},
+		}},
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="2" failures="1">
+  <testsuite name="anticycle" tests="2" failures="1">
+    <testcase name="app/bar" classname="bar">
+      <failure message="import cycle: bar -&gt; baz -&gt; bar" type="ImportCycle"><![CDATA[bar -> baz -> bar
+
+Affected files:
+app/bar/bar.go:5 imports "app/baz"
+]]></failure>
+    </testcase>
+    <testcase name="app/foo" classname="foo"></testcase>
+  </testsuite>
+</testsuites>`
+
+	result, err := ToJUnit(analysis)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestToJUnit_WithEmptyInput(t *testing.T) {
+	analysis := &model.Analysis{
+		Cycles:   []*model.Pkg{},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{}},
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="0" failures="0">
+  <testsuite name="anticycle" tests="0" failures="0"></testsuite>
+</testsuites>`
+
+	result, err := ToJUnit(analysis)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
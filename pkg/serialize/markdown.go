@@ -0,0 +1,204 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anticycle/anticycle/internal/pkg/scan"
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// ToMarkdown takes cycle analysis and produces Markdown report suitable for
+// pull request comments. Report starts with summary table followed by
+// a row for each strongly connected component and collapsible section for
+// each cycle with files and imports of each hop. If analysis has a baseline,
+// cycles are marked as new or existing, and components are compared with
+// packages of baseline cycles, so it is visible which components grew.
+func ToMarkdown(analysis *model.Analysis) (string, error) {
+	cycles := make([][]string, 0)
+	seen := make(map[string]bool)
+	members := make(map[string]bool)
+	largest := 0
+	for _, cycle := range analysis.Metadata.Cycles {
+		key := model.CycleKey(cycle)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		cycles = append(cycles, cycle)
+		names := strings.Fields(key)
+		for _, name := range names {
+			members[name] = true
+		}
+		if len(names) > largest {
+			largest = len(names)
+		}
+	}
+
+	var output strings.Builder
+	output.WriteString("## Anticycle report\n\n")
+	output.WriteString("| Cycles | Packages in cycles | Largest cycle |\n")
+	output.WriteString("| ---: | ---: | ---: |\n")
+	output.WriteString(fmt.Sprintf("| %d | %d | %d |\n", len(cycles), len(members), largest))
+
+	status := make(map[string]string)
+	if analysis.Baseline != nil {
+		output.WriteString("\n| New | Existing | Fixed |\n")
+		output.WriteString("| ---: | ---: | ---: |\n")
+		output.WriteString(fmt.Sprintf("| %d | %d | %d |\n",
+			len(analysis.Baseline.New), len(analysis.Baseline.Existing), len(analysis.Baseline.Fixed)))
+		for _, cycle := range analysis.Baseline.New {
+			status[model.CycleKey(cycle)] = "<b>new</b> "
+		}
+		for _, cycle := range analysis.Baseline.Existing {
+			status[model.CycleKey(cycle)] = "existing "
+		}
+	}
+
+	if components := cycleComponents(analysis); len(components) > 0 {
+		output.WriteString("\n### Components\n\n")
+		if analysis.Baseline != nil {
+			output.WriteString("| Component | Packages | Baseline | Change |\n")
+			output.WriteString("| --- | ---: | ---: | ---: |\n")
+		} else {
+			output.WriteString("| Component | Packages |\n")
+			output.WriteString("| --- | ---: |\n")
+		}
+		for _, component := range components {
+			names := make([]string, 0, len(component.packages))
+			for _, name := range component.packages {
+				names = append(names, fmt.Sprintf("`%s`", markdownEscape(name)))
+			}
+			output.WriteString(fmt.Sprintf("| %s | %d |", strings.Join(names, ", "), len(component.packages)))
+			if analysis.Baseline != nil {
+				output.WriteString(fmt.Sprintf(" %d | %+d |", component.baseline, len(component.packages)-component.baseline))
+			}
+			output.WriteString("\n")
+		}
+	}
+
+	if len(cycles) == 0 {
+		output.WriteString("\nNo cycles found.\n")
+	} else {
+		output.WriteString("\n### Cycles\n")
+	}
+	for _, cycle := range cycles {
+		key := model.CycleKey(cycle)
+		output.WriteString("\n<details>\n")
+		output.WriteString(fmt.Sprintf("<summary>%s%s (%d packages)</summary>\n\n",
+			status[key], strings.Join(cycle, " → "), len(strings.Fields(key))))
+		output.WriteString("| From | To | File |\n")
+		output.WriteString("| --- | --- | --- |\n")
+		for i := 0; i+1 < len(cycle); i++ {
+			for _, file := range hopFiles(analysis, cycle[i], cycle[i+1]) {
+				output.WriteString(fmt.Sprintf("| `%s` | `%s` | %s |\n",
+					markdownEscape(cycle[i]), markdownEscape(cycle[i+1]), file))
+			}
+		}
+		output.WriteString("\n</details>\n")
+	}
+
+	if analysis.Baseline != nil && len(analysis.Baseline.Fixed) > 0 {
+		output.WriteString("\n### Fixed cycles\n\n")
+		for _, cycle := range analysis.Baseline.Fixed {
+			output.WriteString(fmt.Sprintf("- %s\n", strings.Join(cycle, " → ")))
+		}
+	}
+	return strings.TrimRight(output.String(), "\n"), nil
+}
+
+// cycleComponent is a strongly connected component of packages. Baseline is a number
+// of packages in baseline cycles which share a package with the component.
+type cycleComponent struct {
+	packages []string
+	baseline int
+}
+
+// cycleComponents finds strongly connected components with more than one package,
+// the largest first.
+func cycleComponents(analysis *model.Analysis) []*cycleComponent {
+	components := make([]*cycleComponent, 0)
+	for _, scc := range scan.StronglyConnected(scan.CycleDependencies(analysis.Cycles)) {
+		if len(scc) < 2 {
+			continue
+		}
+		component := &cycleComponent{packages: make([]string, 0, len(scc))}
+		for _, node := range scc {
+			component.packages = append(component.packages, analysis.Cycles[node].Name)
+		}
+		sort.Strings(component.packages)
+		if analysis.Baseline != nil {
+			component.baseline = baselineMembers(component.packages, analysis.Baseline)
+		}
+		components = append(components, component)
+	}
+	sort.SliceStable(components, func(i, j int) bool {
+		if len(components[i].packages) != len(components[j].packages) {
+			return len(components[i].packages) > len(components[j].packages)
+		}
+		return strings.Join(components[i].packages, " ") < strings.Join(components[j].packages, " ")
+	})
+	return components
+}
+
+// baselineMembers counts packages of baseline cycles, which are existing and fixed cycles,
+// connected with the packages through shared packages.
+func baselineMembers(packages []string, baseline *model.BaselineReport) int {
+	previous := append(append([][]string{}, baseline.Existing...), baseline.Fixed...)
+	members := make(map[string]bool)
+	connected := make([]bool, len(previous))
+	for changed := true; changed; {
+		changed = false
+		for i, cycle := range previous {
+			if connected[i] {
+				continue
+			}
+			for _, name := range cycle {
+				if members[name] || model.SliceContains(packages, name) {
+					connected[i] = true
+					break
+				}
+			}
+			if connected[i] {
+				changed = true
+				for _, name := range cycle {
+					members[name] = true
+				}
+			}
+		}
+	}
+	return len(members)
+}
+
+// hopFiles returns Markdown links to files of package from which import package to.
+func hopFiles(analysis *model.Analysis, from, to string) []string {
+	links := make([]string, 0)
+	for _, pkg := range analysis.Cycles {
+		if pkg.Name != from {
+			continue
+		}
+		for _, c := range pkg.Cycles {
+			if c.AffectedImport.NameShort != to {
+				continue
+			}
+			target := c.AffectedFile
+			if c.AffectedImport.Line > 0 {
+				target = fmt.Sprintf("%s#L%d", target, c.AffectedImport.Line)
+			}
+			links = append(links, fmt.Sprintf("[%s](%s)", markdownEscape(fileLocation(c)), target))
+		}
+	}
+	if len(links) == 0 {
+		links = append(links, "")
+	}
+	return links
+}
+
+func markdownEscape(s string) string {
+	return strings.Replace(s, "|", `\|`, -1)
+}
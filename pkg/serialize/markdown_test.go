@@ -0,0 +1,101 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToMarkdown(t *testing.T) {
+	analysis := newAnnotationsAnalysis()
+	analysis.Baseline = &model.BaselineReport{
+		New:      [][]string{{"bar", "baz", "bar"}},
+		Existing: [][]string{},
+		Fixed:    [][]string{{"foo", "pas", "foo"}},
+	}
+	expected := `## Anticycle report
+
+| Cycles | Packages in cycles | Largest cycle |
+| ---: | ---: | ---: |
+| 1 | 2 | 2 |
+
+| New | Existing | Fixed |
+| ---: | ---: | ---: |
+| 1 | 0 | 1 |
+
+### Components
+
+| Component | Packages | Baseline | Change |
+| --- | ---: | ---: | ---: |
+| ` + "`bar`, `baz`" + ` | 2 | 0 | +2 |
+
+### Cycles
+
+<details>
+<summary><b>new</b> bar → baz → bar (2 packages)</summary>
+
+| From | To | File |
+| --- | --- | --- |
+| ` + "`bar` | `baz`" + ` | [app/bar/bar.go:5](app/bar/bar.go#L5) |
+| ` + "`baz` | `bar`" + ` | [app/baz/baz.go:3](app/baz/baz.go#L3) |
+
+</details>
+
+### Fixed cycles
+
+- foo → pas → foo`
+
+	result, err := ToMarkdown(analysis)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestToMarkdown_WithGrownComponent(t *testing.T) {
+	analysis := &model.Analysis{
+		Cycles: []*model.Pkg{
+			newDiagramPkg("bar", "app", "app/baz"),
+			newDiagramPkg("baz", "app", "app/bar", "app/qux"),
+			newDiagramPkg("qux", "app", "app/bar"),
+			newDiagramPkg("foo", "lib", "lib/pas"),
+			newDiagramPkg("pas", "lib", "lib/foo"),
+		},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{}},
+		Baseline: &model.BaselineReport{
+			New:      [][]string{{"baz", "qux", "bar", "baz"}},
+			Existing: [][]string{{"bar", "baz", "bar"}, {"foo", "pas", "foo"}},
+			Fixed:    [][]string{},
+		},
+	}
+	expected := "### Components\n\n" +
+		"| Component | Packages | Baseline | Change |\n" +
+		"| --- | ---: | ---: | ---: |\n" +
+		"| `bar`, `baz`, `qux` | 3 | 2 | +1 |\n" +
+		"| `foo`, `pas` | 2 | 2 | +0 |\n"
+
+	result, err := ToMarkdown(analysis)
+	assert.NoError(t, err)
+	assert.Contains(t, result, expected)
+}
+
+func TestToMarkdown_WithEmptyInput(t *testing.T) {
+	analysis := &model.Analysis{
+		Cycles:   []*model.Pkg{},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{}},
+	}
+	expected := `## Anticycle report
+
+| Cycles | Packages in cycles | Largest cycle |
+| ---: | ---: | ---: |
+| 0 | 0 | 0 |
+
+No cycles found.`
+
+	result, err := ToMarkdown(analysis)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
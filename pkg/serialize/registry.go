@@ -0,0 +1,107 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// Formatter renders cycle analysis in a single output format.
+// Name is used to select the format, e.g. -format=text, and Extension
+// is appended to files written by the command line tool.
+type Formatter interface {
+	Name() string
+	Description() string
+	Extension() string
+	Render(w io.Writer, analysis *model.Analysis) error
+}
+
+// AllPackagesFormatter is implemented by formatters which always need
+// all packages, not only packages affected by cycles.
+type AllPackagesFormatter interface {
+	AllPackages() bool
+}
+
+type funcFormatter struct {
+	name, description, extension string
+	allPackages                  bool
+	fn                           func(analysis *model.Analysis) (string, error)
+}
+
+func (f *funcFormatter) Name() string        { return f.name }
+func (f *funcFormatter) Description() string { return f.description }
+func (f *funcFormatter) Extension() string   { return f.extension }
+func (f *funcFormatter) AllPackages() bool   { return f.allPackages }
+
+// Render writes the output followed by a new line, empty output is not written.
+func (f *funcFormatter) Render(w io.Writer, analysis *model.Analysis) error {
+	output, err := f.fn(analysis)
+	if err != nil || output == "" {
+		return err
+	}
+	_, err = fmt.Fprintln(w, output)
+	return err
+}
+
+// NewFormatter creates Formatter from a function which produces the whole output as a string.
+func NewFormatter(name, description, extension string, fn func(analysis *model.Analysis) (string, error)) Formatter {
+	return &funcFormatter{name: name, description: description, extension: extension, fn: fn}
+}
+
+func newAllPackagesFormatter(name, description, extension string, fn func(analysis *model.Analysis) (string, error)) Formatter {
+	return &funcFormatter{name: name, description: description, extension: extension, allPackages: true, fn: fn}
+}
+
+var registry = []Formatter{
+	NewFormatter("text", "Human friendly list of cycles and affected files.", "txt", ToTxt),
+	NewFormatter("json", "Complete analysis, see -schema.", "json", ToJSON),
+	NewFormatter("mermaid", "Mermaid flowchart of packages.", "mmd", ToMermaid),
+	NewFormatter("plantuml", "PlantUML component diagram of packages.", "puml", ToPlantUML),
+	NewFormatter("html", "Interactive, offline page with graph of packages.", "html", ToHTML),
+	newAllPackagesFormatter("junit", "JUnit XML report, a test case per package.", "junit.xml", ToJUnit),
+	NewFormatter("checkstyle", "Checkstyle XML report of imports in cycles.", "checkstyle.xml", ToCheckstyle),
+	NewFormatter("github", "GitHub Actions annotations of imports in cycles.", "github.txt", ToGitHub),
+	NewFormatter("markdown", "Markdown report for pull request comments.", "md", ToMarkdown),
+	newAllPackagesFormatter("csv", "List of imports between packages.", "csv", ToCSV),
+	newAllPackagesFormatter("graphml", "GraphML graph of packages.", "graphml", ToGraphML),
+	newAllPackagesFormatter("gexf", "GEXF graph of packages, e.g. for Gephi.", "gexf", ToGEXF),
+}
+
+// Register adds formatter to the registry, so it can be selected by its name.
+// Names are case insensitive and must be unique.
+func Register(formatter Formatter) error {
+	if Lookup(formatter.Name()) != nil {
+		return fmt.Errorf("format '%v' is already registered", formatter.Name())
+	}
+	registry = append(registry, formatter)
+	return nil
+}
+
+// Lookup returns registered formatter with given name, or nil if there is no such formatter.
+func Lookup(name string) Formatter {
+	for _, formatter := range registry {
+		if strings.EqualFold(formatter.Name(), name) {
+			return formatter
+		}
+	}
+	return nil
+}
+
+// Formatters returns all registered formatters in order of registration.
+func Formatters() []Formatter {
+	formatters := make([]Formatter, len(registry))
+	copy(formatters, registry)
+	return formatters
+}
+
+// NeedsAllPackages checks if formatter always needs all packages.
+func NeedsAllPackages(formatter Formatter) bool {
+	f, ok := formatter.(AllPackagesFormatter)
+	return ok && f.AllPackages()
+}
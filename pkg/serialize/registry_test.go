@@ -0,0 +1,57 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	assert.Equal(t, "json", Lookup("JSON").Name())
+	assert.Nil(t, Lookup("yaml"))
+}
+
+func TestRegister(t *testing.T) {
+	defer func(original []Formatter) { registry = original }(Formatters())
+
+	count := func(analysis *model.Analysis) (string, error) {
+		return strings.Repeat("#", len(analysis.Cycles)), nil
+	}
+	err := Register(NewFormatter("count", "Number of packages.", "count", count))
+	assert.NoError(t, err)
+
+	formatter := Lookup("count")
+	assert.Equal(t, "Number of packages.", formatter.Description())
+	assert.Equal(t, "count", formatter.Extension())
+	assert.False(t, NeedsAllPackages(formatter))
+	assert.Equal(t, "count", Formatters()[len(Formatters())-1].Name())
+
+	var output strings.Builder
+	err = formatter.Render(&output, &model.Analysis{Cycles: []*model.Pkg{model.NewPkg(), model.NewPkg()}})
+	assert.NoError(t, err)
+	assert.Equal(t, "##\n", output.String())
+}
+
+func TestRegister_WithDuplicatedName(t *testing.T) {
+	err := Register(NewFormatter("Text", "", "txt", ToTxt))
+	assert.EqualError(t, err, "format 'Text' is already registered")
+}
+
+func TestRender_WithEmptyOutput(t *testing.T) {
+	var output strings.Builder
+	err := Lookup("github").Render(&output, &model.Analysis{Metadata: &model.AnalysisMeta{}})
+	assert.NoError(t, err)
+	assert.Equal(t, "", output.String())
+}
+
+func TestNeedsAllPackages(t *testing.T) {
+	assert.True(t, NeedsAllPackages(Lookup("junit")))
+	assert.True(t, NeedsAllPackages(Lookup("csv")))
+	assert.False(t, NeedsAllPackages(Lookup("text")))
+}
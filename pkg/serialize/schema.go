@@ -0,0 +1,123 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// JSONSchema produces JSON Schema of ToJSON output. The schema is generated
+// from model types, so it always matches the current schema version.
+func JSONSchema() (string, error) {
+	g := &schemaGenerator{definitions: make(map[string]interface{})}
+	if _, err := g.schemaOf(reflect.TypeOf(model.Analysis{})); err != nil {
+		return "", err
+	}
+
+	schema := map[string]interface{}{
+		"$schema":     "http://json-schema.org/draft-07/schema#",
+		"$id":         fmt.Sprintf("https://github.com/anticycle/anticycle/schema/v%d", model.SchemaVersion),
+		"title":       "Anticycle analysis",
+		"description": fmt.Sprintf("JSON output of anticycle, schema version %d.", model.SchemaVersion),
+		"$ref":        "#/definitions/Analysis",
+		"definitions": g.definitions,
+	}
+	jsonBytes, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
+
+type schemaGenerator struct {
+	definitions map[string]interface{}
+}
+
+// schemaOf returns schema of the type. Structs are stored as definitions
+// and referenced by name. Will return error if the type has no JSON Schema
+// counterpart, e.g. a channel.
+func (g *schemaGenerator) schemaOf(t reflect.Type) (map[string]interface{}, error) {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schemaOf(t.Elem())
+	case reflect.Struct:
+		if _, ok := g.definitions[t.Name()]; !ok {
+			// Reserve the name first, so recursive types do not loop.
+			g.definitions[t.Name()] = nil
+			definition, err := g.structSchema(t)
+			if err != nil {
+				return nil, err
+			}
+			g.definitions[t.Name()] = definition
+		}
+		return map[string]interface{}{"$ref": "#/definitions/" + t.Name()}, nil
+	case reflect.Slice:
+		items, err := g.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items}, nil
+	case reflect.Map:
+		values, err := g.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	}
+	return nil, fmt.Errorf("type %v is not supported by JSON schema generator", t)
+}
+
+// structSchema describes fields of the struct by theirs JSON names. Fields with
+// omitempty are optional, other pointers, slices and maps may be null.
+func (g *schemaGenerator) structSchema(t reflect.Type) (map[string]interface{}, error) {
+	properties := make(map[string]interface{}, t.NumField())
+	required := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		if tag[0] == "-" || field.PkgPath != "" {
+			continue
+		}
+		name := tag[0]
+		if name == "" {
+			name = field.Name
+		}
+		omitEmpty := len(tag) > 1 && tag[1] == "omitempty"
+
+		property, err := g.schemaOf(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %v.%v: %v", t.Name(), field.Name, err)
+		}
+		switch field.Type.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			if !omitEmpty {
+				property = map[string]interface{}{"anyOf": []interface{}{property, map[string]interface{}{"type": "null"}}}
+			}
+		}
+		properties[name] = property
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}, nil
+}
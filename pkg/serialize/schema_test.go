@@ -0,0 +1,47 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONSchema(t *testing.T) {
+	output, err := JSONSchema()
+	assert.NoError(t, err)
+
+	var schema map[string]interface{}
+	err = json.Unmarshal([]byte(output), &schema)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/anticycle/anticycle/schema/v6", schema["$id"])
+	assert.Equal(t, "#/definitions/Analysis", schema["$ref"])
+
+	definitions := schema["definitions"].(map[string]interface{})
+	for _, name := range []string{"Analysis", "Pkg", "File", "ImportInfo", "Cycle", "Tool", "Options", "AnalysisMeta"} {
+		assert.Contains(t, definitions, name)
+	}
+
+	importInfo := definitions["ImportInfo"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"name", "nameShort", "alias"}, importInfo["required"])
+	properties := importInfo["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, properties["line"])
+	assert.Equal(t, map[string]interface{}{"anyOf": []interface{}{
+		map[string]interface{}{"type": "string"},
+		map[string]interface{}{"type": "null"},
+	}}, properties["alias"])
+}
+
+func TestJSONSchema_WithUnsupportedType(t *testing.T) {
+	type report struct {
+		Events chan string `json:"events"`
+	}
+	g := &schemaGenerator{definitions: make(map[string]interface{})}
+	_, err := g.schemaOf(reflect.TypeOf([]*report{}))
+	assert.EqualError(t, err, "field report.Events: type chan string is not supported by JSON schema generator")
+}
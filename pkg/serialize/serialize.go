@@ -8,23 +8,100 @@ package serialize
 import (
 	"encoding/json"
 	"fmt"
+	"path"
 	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/anticycle/anticycle/pkg/model"
 )
 
 // ToJSON takes cycle analysis and produces JSON string.
+// Output is marked with current schema version and is always a valid JSON.
+// Packages are sorted by path, files by path and cycles by file and import,
+// so the same analysis always gives the same output. Keys of imports map
+// are sorted by encoding/json.
 func ToJSON(analysis *model.Analysis) (string, error) {
-	jsonBytes, err := json.Marshal(analysis)
+	versioned := model.Analysis{}
+	if analysis != nil {
+		versioned = *analysis
+	}
+	versioned.SchemaVersion = model.SchemaVersion
+	versioned.Cycles = sortedPackages(versioned.Cycles)
+	if versioned.Metadata == nil {
+		versioned.Metadata = &model.AnalysisMeta{Cycles: make([][]string, 0)}
+	}
+	jsonBytes, err := json.Marshal(&versioned)
 	if err != nil {
 		return "", err
 	}
-	output := string(jsonBytes)
-	if output == "null" || output == "[]" {
-		output = ""
+	return string(jsonBytes), nil
+}
+
+// sortedPackages returns sorted copies of packages, given packages are not modified.
+func sortedPackages(packages []*model.Pkg) []*model.Pkg {
+	sorted := make([]*model.Pkg, 0, len(packages))
+	for _, pkg := range packages {
+		pkgCopy := *pkg
+		pkgCopy.Files = make([]*model.File, len(pkg.Files))
+		copy(pkgCopy.Files, pkg.Files)
+		sort.SliceStable(pkgCopy.Files, func(i, j int) bool {
+			return pkgCopy.Files[i].Path < pkgCopy.Files[j].Path
+		})
+		if pkg.Cycles != nil {
+			pkgCopy.Cycles = make([]*model.Cycle, len(pkg.Cycles))
+			copy(pkgCopy.Cycles, pkg.Cycles)
+			sort.SliceStable(pkgCopy.Cycles, func(i, j int) bool {
+				left, right := pkgCopy.Cycles[i], pkgCopy.Cycles[j]
+				if left.AffectedFile != right.AffectedFile {
+					return left.AffectedFile < right.AffectedFile
+				}
+				return left.AffectedImport.Name < right.AffectedImport.Name
+			})
+		}
+		sorted = append(sorted, &pkgCopy)
 	}
-	return output, nil
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// FromJSON reads cycle analysis produced by ToJSON. Reports without schema
+// version are read as the first version, reports of newer, unknown
+// version are rejected. Empty input is read as analysis without packages.
+func FromJSON(input string) (*model.Analysis, error) {
+	analysis := &model.Analysis{}
+	if strings.TrimSpace(input) != "" {
+		if err := json.Unmarshal([]byte(input), analysis); err != nil {
+			return nil, err
+		}
+	}
+	if analysis.SchemaVersion > model.SchemaVersion {
+		return nil, fmt.Errorf("schema version %d is not supported, the newest known version is %d",
+			analysis.SchemaVersion, model.SchemaVersion)
+	}
+	if analysis.Cycles == nil {
+		analysis.Cycles = make([]*model.Pkg, 0)
+	}
+	if analysis.Metadata == nil {
+		analysis.Metadata = &model.AnalysisMeta{}
+	}
+	if analysis.Metadata.Cycles == nil {
+		analysis.Metadata.Cycles = make([][]string, 0)
+	}
+	for _, pkg := range analysis.Cycles {
+		if pkg.Imports == nil {
+			pkg.Imports = make(map[string]*model.ImportInfo)
+		}
+		if pkg.Files == nil {
+			pkg.Files = make([]*model.File, 0)
+		}
+	}
+	return analysis, nil
 }
 
 // ToTxt takes cycle analysis and produces human friendly text output.
@@ -39,7 +116,14 @@ func ToTxt(analysis *model.Analysis) (string, error) {
 	// To easy create list of files grouped by imports per package
 	// we need to create input data based on slice of packages.
 	input := make(map[string]map[string][]string)
+	// Owners of affected files are known only if owners are analyzed.
+	owners := make(map[string][]string)
 	for _, pkg := range packages {
+		for _, cycle := range pkg.Cycles {
+			if len(cycle.Owners) > 0 {
+				owners[cycle.AffectedFile] = cycle.Owners
+			}
+		}
 		pkgOrder = append(pkgOrder, pkg.Name)
 		impsOrder[pkg.Name] = make([]string, 0)
 
@@ -53,10 +137,10 @@ func ToTxt(analysis *model.Analysis) (string, error) {
 		// append all files to corresponding imports without duplicates
 		for _, file := range pkg.Files {
 			for _, imp := range file.Imports {
-				if !sliceContains(impsOrder[pkg.Name], imp.Name) {
+				if !model.SliceContains(impsOrder[pkg.Name], imp.Name) {
 					impsOrder[pkg.Name] = append(impsOrder[pkg.Name], imp.Name)
 				}
-				if sliceContains(input[pkg.Name][imp.Name], file.Path) {
+				if model.SliceContains(input[pkg.Name][imp.Name], file.Path) {
 					continue
 				}
 				input[pkg.Name][imp.Name] = append(input[pkg.Name][imp.Name], file.Path)
@@ -83,7 +167,11 @@ func ToTxt(analysis *model.Analysis) (string, error) {
 
 			var filesList []string
 			for _, file := range files {
-				filesList = append(filesList, fmt.Sprintf("   %s", file))
+				if fileOwners, ok := owners[file]; ok {
+					filesList = append(filesList, fmt.Sprintf("   %s %s", file, strings.Join(fileOwners, " ")))
+				} else {
+					filesList = append(filesList, fmt.Sprintf("   %s", file))
+				}
 			}
 			sort.Strings(filesList)
 			out.WriteString(fmt.Sprintf("%s\n", strings.Join(filesList, "\n")))
@@ -91,14 +179,213 @@ func ToTxt(analysis *model.Analysis) (string, error) {
 		output.WriteString(fmt.Sprintf("%s\n", out.String()))
 	}
 
+	if analysis.Stability != nil {
+		output.WriteString(stabilityToTxt(analysis.Stability))
+	}
+	if analysis.Levels != nil {
+		output.WriteString(levelsToTxt(analysis.Levels))
+	}
+	if analysis.Orphans != nil {
+		output.WriteString(orphansToTxt(analysis.Orphans))
+	}
+	if analysis.Visibility != nil {
+		output.WriteString(visibilityToTxt(analysis.Visibility))
+	}
+	if analysis.Suppressions != nil {
+		output.WriteString(suppressionsToTxt(analysis.Suppressions))
+	}
+	if analysis.Allowlist != nil {
+		output.WriteString(allowlistToTxt(analysis.Allowlist))
+	}
+	if analysis.Owners != nil {
+		output.WriteString(ownersToTxt(analysis.Owners))
+	}
+
 	return strings.TrimRight(output.String(), "\r\n"), nil
 }
 
-func sliceContains(slice []string, str string) bool {
-	for _, s := range slice {
-		if s == str {
-			return true
+func stabilityToTxt(report *model.StabilityReport) string {
+	var output strings.Builder
+	output.WriteString("Stability\n\n")
+
+	table := tabwriter.NewWriter(&output, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(table, "PACKAGE\tCa\tCe\tI\tPATH")
+	for _, pkg := range report.Packages {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%.2f\t%s\n", pkg.Name, pkg.Afferent, pkg.Efferent, pkg.Instability, pkg.Path)
+	}
+	table.Flush()
+
+	if len(report.Violations) > 0 {
+		output.WriteString(fmt.Sprintf("\nFound %d stable dependencies violations\n\n", len(report.Violations)))
+		for _, v := range report.Violations {
+			output.WriteString(fmt.Sprintf("[%s -> %s] %.2f -> %.2f (+%.2f)\n", v.From.Name, v.To.Name, v.From.Instability, v.To.Instability, v.Delta))
+			output.WriteString(fmt.Sprintf("   %s\n   %s\n", v.From.Path, v.To.Path))
+		}
+	}
+	output.WriteString("\n")
+	return output.String()
+}
+
+func levelsToTxt(levels *model.Levelization) string {
+	var output strings.Builder
+	output.WriteString("Levels\n\n")
+
+	table := tabwriter.NewWriter(&output, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(table, "LEVEL\tPACKAGES")
+	for _, layer := range levels.Layers {
+		names := make([]string, 0, len(layer.Components))
+		for _, component := range layer.Components {
+			names = append(names, componentName(component))
+		}
+		fmt.Fprintf(table, "%d\t%s\n", layer.Level, strings.Join(names, " "))
+	}
+	table.Flush()
+
+	if len(levels.LongestChain) > 0 {
+		chain := make([]string, 0, len(levels.LongestChain))
+		for _, component := range levels.LongestChain {
+			chain = append(chain, componentName(component))
+		}
+		output.WriteString(fmt.Sprintf("\nLongest chain (%d)\n\n", len(chain)))
+		output.WriteString(fmt.Sprintf("%s\n", strings.Join(chain, " -> ")))
+	}
+	output.WriteString("\n")
+	return output.String()
+}
+
+func orphansToTxt(orphans *model.OrphanReport) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Found %d orphan packages\n\n", len(orphans.Packages)))
+
+	table := tabwriter.NewWriter(&output, 0, 0, 3, ' ', 0)
+	for _, orphan := range orphans.Packages {
+		fmt.Fprintf(table, "%s\t%s\n", orphan.Name, orphan.Path)
+	}
+	table.Flush()
+
+	if len(orphans.Allowed) > 0 {
+		output.WriteString(fmt.Sprintf("\nAllowed %d orphan packages\n\n", len(orphans.Allowed)))
+		table := tabwriter.NewWriter(&output, 0, 0, 3, ' ', 0)
+		for _, orphan := range orphans.Allowed {
+			fmt.Fprintf(table, "%s\t%s\n", orphan.Name, orphan.Path)
+		}
+		table.Flush()
+	}
+	output.WriteString("\n")
+	return output.String()
+}
+
+func visibilityToTxt(visibility *model.VisibilityReport) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Found %d internal visibility violations\n\n", len(visibility.Violations)))
+	for _, v := range visibility.Violations {
+		output.WriteString(fmt.Sprintf("[%s -> %s] \"%s\"\n", v.Pkg, v.Import.NameShort, v.Import.Name))
+		output.WriteString(fmt.Sprintf("   %s:%d\n", v.File, v.Import.Line))
+	}
+	output.WriteString("\n")
+	return output.String()
+}
+
+func suppressionsToTxt(suppressions *model.SuppressionReport) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Found %d suppressions\n\n", len(suppressions.Active)))
+	for _, s := range suppressions.Active {
+		output.WriteString(suppressionToTxt(s))
+	}
+	if len(suppressions.Stale) > 0 {
+		output.WriteString(fmt.Sprintf("\nWarning: found %d stale suppressions, which do not match any import in a cycle\n\n", len(suppressions.Stale)))
+		for _, s := range suppressions.Stale {
+			output.WriteString(suppressionToTxt(s))
+		}
+	}
+	output.WriteString("\n")
+	return output.String()
+}
+
+func suppressionToTxt(suppression *model.Suppression) string {
+	reason := suppression.Reason
+	if reason == "" {
+		reason = "no reason given"
+	}
+	return fmt.Sprintf("[%s -> %s] \"%s\" %s: %s\n   %s:%d\n", suppression.Pkg, path.Base(suppression.Import),
+		suppression.Import, suppression.Directive, reason, suppression.File, suppression.Line)
+}
+
+func allowlistToTxt(allowlist *model.AllowlistReport) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Warning: found %d allowed cycles\n\n", len(allowlist.Allowed)))
+	for _, allowed := range allowlist.Allowed {
+		output.WriteString(allowedCycleToTxt(allowed))
+	}
+	if len(allowlist.Expired) > 0 {
+		output.WriteString(fmt.Sprintf("\nError: found %d allowed cycles which expired\n\n", len(allowlist.Expired)))
+		for _, allowed := range allowlist.Expired {
+			output.WriteString(allowedCycleToTxt(allowed))
+		}
+	}
+	if len(allowlist.NotAllowed) > 0 {
+		output.WriteString(fmt.Sprintf("\nError: found %d cycles which are not allowed\n\n", len(allowlist.NotAllowed)))
+		for _, cycle := range allowlist.NotAllowed {
+			output.WriteString(fmt.Sprintf("%s\n", strings.Join(cycle, " -> ")))
+		}
+	}
+	if len(allowlist.Unused) > 0 {
+		output.WriteString(fmt.Sprintf("\nWarning: found %d allowed cycles which do not exist anymore\n\n", len(allowlist.Unused)))
+		for _, allowed := range allowlist.Unused {
+			output.WriteString(allowedCycleToTxt(allowed))
+		}
+	}
+	output.WriteString("\n")
+	return output.String()
+}
+
+func allowedCycleToTxt(allowed *model.AllowedCycle) string {
+	line := fmt.Sprintf("%s\n   owner: %s, expires: %s\n", strings.Join(allowed.Cycle, " -> "), allowed.Owner, allowed.Expires)
+	if allowed.Reason != "" {
+		line += fmt.Sprintf("   %s\n", allowed.Reason)
+	}
+	return line
+}
+
+func ownersToTxt(owners *model.OwnersReport) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Owners from %s\n", owners.File))
+	if len(owners.Unowned) > 0 {
+		output.WriteString(fmt.Sprintf("\nWarning: found %d files with imports in cycles without owners\n\n", len(owners.Unowned)))
+		for _, file := range owners.Unowned {
+			output.WriteString(fmt.Sprintf("%s\n", file))
+		}
+	}
+	if owners.Teams != nil {
+		output.WriteString("\nTeams\n\n")
+		table := tabwriter.NewWriter(&output, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(table, "FROM\tTO\tIMPORTS\tCYCLE")
+		for _, edge := range owners.Teams {
+			fmt.Fprintf(table, "%s\t%s\t%d\t%v\n", edge.From, edge.To, edge.Imports, edge.Cycle)
 		}
+		table.Flush()
+
+		output.WriteString(fmt.Sprintf("\nFound %d cycles between teams\n\n", len(owners.TeamCycles)))
+		for _, cycle := range owners.TeamCycles {
+			output.WriteString(fmt.Sprintf("%s\n", strings.Join(cycle, " -> ")))
+		}
+	}
+	output.WriteString("\n")
+	return output.String()
+}
+
+// componentName joins packages of the cycle in braces, e.g. {bar,baz}.
+func componentName(component *model.Component) string {
+	if !component.Cycle {
+		return strings.Join(component.Packages, ",")
+	}
+	return fmt.Sprintf("{%s}", strings.Join(component.Packages, ","))
+}
+
+// section writes title with number of lines followed by lines, if any.
+func section(title string, lines []string) string {
+	if len(lines) == 0 {
+		return fmt.Sprintf("%s (0)", title)
 	}
-	return false
+	return fmt.Sprintf("%s (%d)\n\n%s", title, len(lines), strings.Join(lines, "\n"))
 }
@@ -24,7 +24,7 @@ func TestToJSON(t *testing.T) {
 	jsonStr, err := ToJSON(analysis)
 	assert.NoError(t, err)
 
-	expected := `{"cycles":[{"name":"test/pkg","path":"","imports":{"internal":null},"files":[],"haveCycle":false}],"metadata":{"cycles":[]}}`
+	expected := `{"schemaVersion":6,"cycles":[{"name":"test/pkg","path":"","imports":{"internal":null},"files":[],"haveCycle":false}],"metadata":{"cycles":[]}}`
 	assert.Equal(t, expected, jsonStr)
 }
 
@@ -36,7 +36,36 @@ func TestToJSON_WithEmptyInput(t *testing.T) {
 
 	jsonStr, err := ToJSON(analysis)
 	assert.NoError(t, err)
-	assert.Equal(t, "{\"cycles\":[],\"metadata\":{\"cycles\":[]}}", jsonStr)
+	assert.Equal(t, "{\"schemaVersion\":6,\"cycles\":[],\"metadata\":{\"cycles\":[]}}", jsonStr)
+}
+
+func TestToJSON_WithNilAnalysis(t *testing.T) {
+	jsonStr, err := ToJSON(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"schemaVersion":6,"cycles":[],"metadata":{"cycles":[]}}`, jsonStr)
+}
+
+func TestToJSON_SortsPackagesAndFiles(t *testing.T) {
+	bar := model.NewPkg()
+	bar.Name = "bar"
+	bar.Path = "app/bar"
+	bar.Files = []*model.File{{Path: "app/bar/z.go"}, {Path: "app/bar/a.go"}}
+	foo := model.NewPkg()
+	foo.Name = "foo"
+	foo.Path = "app/foo"
+	analysis := &model.Analysis{
+		Cycles:   []*model.Pkg{foo, bar},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{}},
+	}
+
+	jsonStr, err := ToJSON(analysis)
+	assert.NoError(t, err)
+	expected := `{"schemaVersion":6,"cycles":[{"name":"bar","path":"app/bar","imports":{},"files":[` +
+		`{"path":"app/bar/a.go","imports":null},{"path":"app/bar/z.go","imports":null}],"haveCycle":false},` +
+		`{"name":"foo","path":"app/foo","imports":{},"files":[],"haveCycle":false}],"metadata":{"cycles":[]}}`
+	assert.Equal(t, expected, jsonStr)
+	assert.Equal(t, "foo", analysis.Cycles[0].Name, "input should not be modified")
+	assert.Equal(t, "app/bar/z.go", bar.Files[0].Path, "input should not be modified")
 }
 
 func ExampleToJSON() {
@@ -50,7 +79,59 @@ func ExampleToJSON() {
 
 	jsonStr, _ := ToJSON(analysis)
 	fmt.Print(jsonStr)
-	// Output: {"cycles":[{"name":"test/pkg","path":"","imports":{"internal":null},"files":[],"haveCycle":false}],"metadata":{"cycles":[]}}
+	// Output: {"schemaVersion":6,"cycles":[{"name":"test/pkg","path":"","imports":{"internal":null},"files":[],"haveCycle":false}],"metadata":{"cycles":[]}}
+}
+
+func TestFromJSON(t *testing.T) {
+	input := `{"schemaVersion":6,"cycles":[{"name":"bar","path":"app/bar","importPath":"example.com/app/bar",` +
+		`"module":"example.com/app","imports":{"example.com/app/baz":{"name":"example.com/app/baz",` +
+		`"nameShort":"baz","alias":"b","kind":"module"}},"files":[{"path":"app/bar/bar.go","imports":` +
+		`[{"name":"example.com/app/baz","nameShort":"baz","alias":"b","kind":"module","line":3}]}],` +
+		`"cycles":[{"affectedImport":{"name":"example.com/app/baz","nameShort":"baz","alias":"b",` +
+		`"kind":"module","line":3},"affectedFile":"app/bar/bar.go"}],"haveCycle":true}],` +
+		`"metadata":{"cycles":[["bar","baz","bar"]]},"levels":{"layers":[],"longestChain":[]}}`
+
+	analysis, err := FromJSON(input)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", *analysis.Cycles[0].Files[0].Imports[0].Alias)
+	assert.Equal(t, 3, analysis.Cycles[0].Cycles[0].AffectedImport.Line)
+
+	output, err := ToJSON(analysis)
+	assert.NoError(t, err)
+	assert.Equal(t, input, output)
+}
+
+func TestFromJSON_WithFirstSchemaVersion(t *testing.T) {
+	analysis, err := FromJSON(`{"schemaVersion":1,"cycles":[],"metadata":{"cycles":[["bar","baz","bar"]]}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, analysis.SchemaVersion)
+	assert.Nil(t, analysis.Tool)
+	assert.Equal(t, [][]string{{"bar", "baz", "bar"}}, analysis.Metadata.Cycles)
+}
+
+func TestFromJSON_WithoutSchemaVersion(t *testing.T) {
+	analysis, err := FromJSON(`{"cycles":[{"name":"bar","path":"app/bar","imports":{},"haveCycle":false}],"metadata":null}`)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, analysis.SchemaVersion)
+	assert.Equal(t, [][]string{}, analysis.Metadata.Cycles)
+	assert.Equal(t, []*model.File{}, analysis.Cycles[0].Files)
+}
+
+func TestFromJSON_WithEmptyInput(t *testing.T) {
+	analysis, err := FromJSON("")
+	assert.NoError(t, err)
+	assert.Empty(t, analysis.Cycles)
+	assert.Empty(t, analysis.Metadata.Cycles)
+}
+
+func TestFromJSON_WithNewerSchemaVersion(t *testing.T) {
+	_, err := FromJSON(`{"schemaVersion":99,"cycles":[]}`)
+	assert.EqualError(t, err, "schema version 99 is not supported, the newest known version is 6")
+}
+
+func TestFromJSON_WithInvalidInput(t *testing.T) {
+	_, err := FromJSON(`{"cycles":`)
+	assert.Error(t, err)
 }
 
 func TestToTxt(t *testing.T) {
@@ -136,3 +217,149 @@ func TestToTxt(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 }
+
+func TestToTxt_WithStability(t *testing.T) {
+	core := &model.Stability{Name: "core", Path: "app/core", Afferent: 2, Efferent: 1, Instability: 1.0 / 3}
+	helper := &model.Stability{Name: "helper", Path: "app/helper", Afferent: 1, Efferent: 1, Instability: 0.5}
+	analysis := &model.Analysis{
+		Cycles:   []*model.Pkg{},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{}},
+		Stability: &model.StabilityReport{
+			Packages: []*model.Stability{core, helper},
+			Violations: []*model.SDPViolation{
+				{From: core, To: helper, Delta: helper.Instability - core.Instability},
+			},
+		},
+	}
+	expected := "Stability\n\n" +
+		"PACKAGE   Ca   Ce   I      PATH\n" +
+		"core      2    1    0.33   app/core\n" +
+		"helper    1    1    0.50   app/helper\n" +
+		"\nFound 1 stable dependencies violations\n\n" +
+		"[core -> helper] 0.33 -> 0.50 (+0.17)\n" +
+		"   app/core\n" +
+		"   app/helper"
+
+	result, err := ToTxt(analysis)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestToTxt_WithLevels(t *testing.T) {
+	foo := &model.Component{Packages: []string{"foo"}}
+	cycle := &model.Component{Packages: []string{"bar", "baz"}, Cycle: true}
+	analysis := &model.Analysis{
+		Cycles:   []*model.Pkg{},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{}},
+		Levels: &model.Levelization{
+			Layers: []*model.Layer{
+				{Level: 0, Components: []*model.Component{foo}},
+				{Level: 1, Components: []*model.Component{cycle, {Packages: []string{"pas"}}}},
+			},
+			LongestChain: []*model.Component{cycle, foo},
+		},
+	}
+	expected := "Levels\n\n" +
+		"LEVEL   PACKAGES\n" +
+		"0       foo\n" +
+		"1       {bar,baz} pas\n" +
+		"\nLongest chain (2)\n\n" +
+		"{bar,baz} -> foo"
+
+	result, err := ToTxt(analysis)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestToTxt_WithOrphans(t *testing.T) {
+	analysis := &model.Analysis{
+		Cycles:   []*model.Pkg{},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{}},
+		Orphans: &model.OrphanReport{
+			Packages: []*model.Orphan{{Name: "dead", Path: "app/dead"}},
+			Allowed:  []*model.Orphan{{Name: "client", Path: "pkg/client"}},
+		},
+	}
+	expected := "Found 1 orphan packages\n\n" +
+		"dead   app/dead\n" +
+		"\nAllowed 1 orphan packages\n\n" +
+		"client   pkg/client"
+
+	result, err := ToTxt(analysis)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestToTxt_WithVisibility(t *testing.T) {
+	analysis := &model.Analysis{
+		Cycles:   []*model.Pkg{},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{}},
+		Visibility: &model.VisibilityReport{
+			Violations: []*model.VisibilityViolation{
+				{
+					Pkg:    "app",
+					File:   "app/app.go",
+					Import: &model.ImportInfo{Name: "example.com/a/internal/c", NameShort: "c", Line: 7},
+				},
+			},
+		},
+	}
+	expected := "Found 1 internal visibility violations\n\n" +
+		"[app -> c] \"example.com/a/internal/c\"\n" +
+		"   app/app.go:7"
+
+	result, err := ToTxt(analysis)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestToTxt_WithOwners(t *testing.T) {
+	affected := &model.ImportInfo{Name: "app/store", NameShort: "store", Line: 3}
+	analysis := &model.Analysis{
+		Cycles: []*model.Pkg{
+			{
+				Name:    "core",
+				Path:    "app/core",
+				Imports: map[string]*model.ImportInfo{"app/store": affected},
+				Files: []*model.File{
+					{Path: "app/core/core.go", Imports: []*model.ImportInfo{affected}},
+					{Path: "app/core/legacy.go", Imports: []*model.ImportInfo{affected}},
+				},
+				Cycles: []*model.Cycle{
+					{AffectedImport: affected, AffectedFile: "app/core/core.go", Owners: []string{"@org/core", "@dba"}},
+					{AffectedImport: affected, AffectedFile: "app/core/legacy.go"},
+				},
+				HaveCycle: true,
+			},
+		},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{{"core", "store", "core"}}},
+		Owners: &model.OwnersReport{
+			File:    "CODEOWNERS",
+			Unowned: []string{"app/core/legacy.go"},
+			Teams: []*model.TeamEdge{
+				{From: "@org/core", To: "@org/data", Imports: 2, Cycle: true},
+				{From: "@org/data", To: "@org/core", Imports: 1, Cycle: true},
+			},
+			TeamCycles: [][]string{{"@org/core", "@org/data", "@org/core"}},
+		},
+	}
+	expected := "Found 1 cycles\n\n" +
+		"core -> store -> core\n\n" +
+		"Details\n\n" +
+		"[core -> store] \"app/store\"\n" +
+		"   app/core/core.go @org/core @dba\n" +
+		"   app/core/legacy.go\n\n" +
+		"Owners from CODEOWNERS\n\n" +
+		"Warning: found 1 files with imports in cycles without owners\n\n" +
+		"app/core/legacy.go\n\n" +
+		"Teams\n\n" +
+		"FROM        TO          IMPORTS   CYCLE\n" +
+		"@org/core   @org/data   2         true\n" +
+		"@org/data   @org/core   1         true\n\n" +
+		"Found 1 cycles between teams\n\n" +
+		"@org/core -> @org/data -> @org/core"
+
+	result, err := ToTxt(analysis)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
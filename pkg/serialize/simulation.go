@@ -0,0 +1,46 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// SimulationToJSON takes cycles before and after simulated edits and produces JSON string.
+func SimulationToJSON(report *model.SimulationReport) (string, error) {
+	jsonBytes, err := json.Marshal(report)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
+
+// SimulationToTxt takes cycles before and after simulated edits and produces
+// human friendly text output, ending with a summary of resolved and introduced cycles.
+func SimulationToTxt(report *model.SimulationReport) (string, error) {
+	edits := make([]string, 0, len(report.Edits))
+	for _, edit := range report.Edits {
+		edits = append(edits, fmt.Sprintf("%s %s %s", edit.Op, edit.File, edit.Target))
+	}
+	sections := []string{
+		section("Edits", edits),
+		section("Cycles before", cyclesToLines(report.Before, " -> ")),
+		section("Cycles after", cyclesToLines(report.After, " -> ")),
+	}
+	if len(report.Resolved) > 0 {
+		sections = append(sections, section("Resolved cycles", cyclesToLines(report.Resolved, " -> ")))
+	}
+	if len(report.Introduced) > 0 {
+		sections = append(sections, section("Introduced cycles", cyclesToLines(report.Introduced, " -> ")))
+	}
+	if len(report.Resolved) == 0 && len(report.Introduced) == 0 {
+		sections = append(sections, "Edits do not change cycles")
+	}
+	return strings.Join(sections, "\n\n"), nil
+}
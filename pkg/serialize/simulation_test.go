@@ -0,0 +1,82 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSimulationReport() *model.SimulationReport {
+	return &model.SimulationReport{
+		Edits:      []*model.Edit{{Op: model.EditMove, File: "models/errors.go", Target: "errs"}},
+		Before:     [][]string{{"api", "models", "api"}},
+		After:      [][]string{{"api", "errs", "api"}},
+		Resolved:   [][]string{{"api", "models", "api"}},
+		Introduced: [][]string{{"api", "errs", "api"}},
+	}
+}
+
+func TestSimulationToTxt(t *testing.T) {
+	expected := `Edits (1)
+
+move models/errors.go errs
+
+Cycles before (1)
+
+api -> models -> api
+
+Cycles after (1)
+
+api -> errs -> api
+
+Resolved cycles (1)
+
+api -> models -> api
+
+Introduced cycles (1)
+
+api -> errs -> api`
+
+	output, err := SimulationToTxt(newSimulationReport())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, output)
+}
+
+func TestSimulationToTxt_WithoutChanges(t *testing.T) {
+	report := newSimulationReport()
+	report.After = report.Before
+	report.Resolved = [][]string{}
+	report.Introduced = [][]string{}
+	expected := `Edits (1)
+
+move models/errors.go errs
+
+Cycles before (1)
+
+api -> models -> api
+
+Cycles after (1)
+
+api -> models -> api
+
+Edits do not change cycles`
+
+	output, err := SimulationToTxt(report)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, output)
+}
+
+func TestSimulationToJSON(t *testing.T) {
+	expected := `{"edits":[{"op":"move","file":"models/errors.go","target":"errs"}],` +
+		`"before":[["api","models","api"]],"after":[["api","errs","api"]],` +
+		`"resolved":[["api","models","api"]],"introduced":[["api","errs","api"]]}`
+
+	output, err := SimulationToJSON(newSimulationReport())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, output)
+}
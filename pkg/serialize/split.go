@@ -0,0 +1,56 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// SplitToJSON takes split plan of a package and produces JSON string.
+func SplitToJSON(plan *model.SplitPlan) (string, error) {
+	jsonBytes, err := json.Marshal(plan)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
+
+// SplitToTxt takes split plan of a package and produces human friendly text output,
+// with files to move and cycles changed by every proposal.
+func SplitToTxt(plan *model.SplitPlan) (string, error) {
+	if len(plan.Cycles) == 0 {
+		return fmt.Sprintf("Package %s (%s) is not in a cycle", plan.Pkg, plan.Path), nil
+	}
+	sections := []string{
+		fmt.Sprintf("Package %s (%s)", plan.Pkg, plan.Path),
+		section("Cycles", cyclesToLines(plan.Cycles, " -> ")),
+	}
+	for _, proposal := range plan.Proposals {
+		lines := make([]string, 0, len(proposal.Files)+len(proposal.Resolved)+len(proposal.Introduced))
+		lines = append(lines, proposal.Files...)
+		if proposal.Whole {
+			lines = append(lines, "   every file of the package imports it or uses files which do, split is not possible")
+			sections = append(sections, fmt.Sprintf("Imports of %s can not be split from %s (%d)\n\n%s",
+				proposal.Import, plan.Pkg, len(proposal.Files), strings.Join(lines, "\n")))
+			continue
+		}
+		for _, cycle := range proposal.Resolved {
+			lines = append(lines, "   resolves "+strings.Join(cycle, " -> "))
+		}
+		for _, cycle := range proposal.Introduced {
+			lines = append(lines, "   introduces "+strings.Join(cycle, " -> "))
+		}
+		if len(proposal.Resolved) == 0 && len(proposal.Introduced) == 0 {
+			lines = append(lines, "   does not change cycles")
+		}
+		sections = append(sections, fmt.Sprintf("Move to %s to break imports of %s (%d)\n\n%s",
+			proposal.Target, proposal.Import, len(proposal.Files), strings.Join(lines, "\n")))
+	}
+	return strings.Join(sections, "\n\n"), nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSplitPlan() *model.SplitPlan {
+	return &model.SplitPlan{
+		Pkg:    "models",
+		Path:   "app/models",
+		Cycles: [][]string{{"models", "api", "models"}, {"models", "db", "models"}},
+		Files: []*model.FileDeps{
+			{File: "models/model.go", DependsOn: []string{}},
+			{File: "models/render.go", DependsOn: []string{"models/model.go"}},
+			{File: "models/store.go", DependsOn: []string{"models/model.go"}},
+		},
+		Proposals: []*model.SplitProposal{
+			{
+				Import:     "app/api",
+				Target:     "modelsapi",
+				Files:      []string{"models/render.go"},
+				Edits:      []*model.Edit{{Op: model.EditMove, File: "models/render.go", Target: "modelsapi"}},
+				Resolved:   [][]string{{"models", "api", "models"}},
+				Introduced: [][]string{{"modelsapi", "api", "modelsapi"}},
+			},
+			{
+				Import:     "app/db",
+				Target:     "modelsdb",
+				Files:      []string{"models/store.go"},
+				Edits:      []*model.Edit{{Op: model.EditMove, File: "models/store.go", Target: "modelsdb"}},
+				Resolved:   [][]string{},
+				Introduced: [][]string{},
+			},
+			{
+				Import:     "app/cache",
+				Target:     "modelscache",
+				Files:      []string{"models/model.go", "models/render.go", "models/store.go"},
+				Whole:      true,
+				Edits:      []*model.Edit{},
+				Resolved:   [][]string{},
+				Introduced: [][]string{},
+			},
+		},
+	}
+}
+
+func TestSplitToTxt(t *testing.T) {
+	expected := `Package models (app/models)
+
+Cycles (2)
+
+models -> api -> models
+models -> db -> models
+
+Move to modelsapi to break imports of app/api (1)
+
+models/render.go
+   resolves models -> api -> models
+   introduces modelsapi -> api -> modelsapi
+
+Move to modelsdb to break imports of app/db (1)
+
+models/store.go
+   does not change cycles
+
+Imports of app/cache can not be split from models (3)
+
+models/model.go
+models/render.go
+models/store.go
+   every file of the package imports it or uses files which do, split is not possible`
+
+	output, err := SplitToTxt(newSplitPlan())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, output)
+}
+
+func TestSplitToTxt_WithoutCycles(t *testing.T) {
+	output, err := SplitToTxt(&model.SplitPlan{Pkg: "models", Path: "app/models"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Package models (app/models) is not in a cycle", output)
+}
+
+func TestSplitToJSON(t *testing.T) {
+	plan := newSplitPlan()
+	plan.Proposals = plan.Proposals[1:2]
+	expected := `{"pkg":"models","path":"app/models",` +
+		`"cycles":[["models","api","models"],["models","db","models"]],` +
+		`"files":[{"file":"models/model.go","dependsOn":[]},` +
+		`{"file":"models/render.go","dependsOn":["models/model.go"]},` +
+		`{"file":"models/store.go","dependsOn":["models/model.go"]}],` +
+		`"proposals":[{"import":"app/db","target":"modelsdb","files":["models/store.go"],"whole":false,` +
+		`"edits":[{"op":"move","file":"models/store.go","target":"modelsdb"}],"resolved":[],"introduced":[]}]}`
+
+	output, err := SplitToJSON(plan)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, output)
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// TemplateFuncs are helper functions available in user-supplied templates:
+//
+//	cycle   joins packages of a cycle with arrows, e.g. "bar -> baz -> bar"
+//	join    joins list of strings with a separator
+//	rel     returns path relative to a base directory, e.g. {{rel $.Root .Path}}
+//	count   returns number of elements of a list or a map, 0 for nil
+//	plural  returns singular or plural form of a word for a number
+var TemplateFuncs = template.FuncMap{
+	"cycle": func(cycle []string) string {
+		return strings.Join(cycle, " -> ")
+	},
+	"join": func(list []string, sep string) string {
+		return strings.Join(list, sep)
+	},
+	"rel": func(base, target string) string {
+		rel, err := filepath.Rel(base, target)
+		if err != nil {
+			return target
+		}
+		return filepath.ToSlash(rel)
+	},
+	"count": func(v interface{}) int {
+		value := reflect.ValueOf(v)
+		switch value.Kind() {
+		case reflect.Array, reflect.Chan, reflect.Map, reflect.Slice, reflect.String:
+			return value.Len()
+		}
+		return 0
+	},
+	"plural": func(n int, singular, plural string) string {
+		if n == 1 {
+			return singular
+		}
+		return plural
+	},
+}
+
+type templateFormatter struct {
+	name, extension string
+	template        *template.Template
+}
+
+func (f *templateFormatter) Name() string        { return f.name }
+func (f *templateFormatter) Description() string { return "User-supplied text/template." }
+func (f *templateFormatter) Extension() string   { return f.extension }
+
+// Render executes the template against the analysis.
+func (f *templateFormatter) Render(w io.Writer, analysis *model.Analysis) error {
+	return f.template.Execute(w, analysis)
+}
+
+// NewTemplateFormatter parses text/template which is executed against model.Analysis
+// with TemplateFuncs. Output of the template is written as is.
+func NewTemplateFormatter(name, extension, text string) (Formatter, error) {
+	tmpl, err := template.New(name).Funcs(TemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &templateFormatter{name: name, extension: extension, template: tmpl}, nil
+}
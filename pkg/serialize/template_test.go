@@ -0,0 +1,53 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTemplateFormatter(t *testing.T) {
+	text := `{{$n := len .Metadata.Cycles}}{{$n}} {{plural $n "cycle" "cycles"}} in {{.Root}}
+{{range .Metadata.Cycles}}{{cycle .}} ({{join . ", "}})
+{{end}}{{range .Cycles}}{{rel $.Root .Path}} {{count .Files}} {{count .Cycles}}
+{{end}}`
+	formatter, err := NewTemplateFormatter("slack", "md", text)
+	assert.NoError(t, err)
+	assert.Equal(t, "slack", formatter.Name())
+	assert.Equal(t, "md", formatter.Extension())
+	assert.False(t, NeedsAllPackages(formatter))
+
+	bar := model.NewPkg()
+	bar.Path = "project/bar"
+	bar.Files = []*model.File{{Path: "project/bar/bar.go"}}
+	analysis := &model.Analysis{
+		Root:     "project",
+		Cycles:   []*model.Pkg{bar},
+		Metadata: &model.AnalysisMeta{Cycles: [][]string{{"bar", "baz", "bar"}}},
+	}
+
+	var output strings.Builder
+	err = formatter.Render(&output, analysis)
+	assert.NoError(t, err)
+	assert.Equal(t, "1 cycle in project\nbar -> baz -> bar (bar, baz, bar)\nbar 1 0\n", output.String())
+}
+
+func TestNewTemplateFormatter_WithParseError(t *testing.T) {
+	_, err := NewTemplateFormatter("broken", "txt", "{{cycles .}}")
+	assert.EqualError(t, err, `template: broken:1: function "cycles" not defined`)
+}
+
+func TestNewTemplateFormatter_WithExecutionError(t *testing.T) {
+	formatter, err := NewTemplateFormatter("broken", "txt", "{{.Unknown}}")
+	assert.NoError(t, err)
+
+	var output strings.Builder
+	err = formatter.Render(&output, &model.Analysis{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,28 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anticycle/anticycle/pkg/model"
+)
+
+// WatchToTxt renders cycles introduced and resolved since the previous check
+// of -watch mode, a line per cycle prefixed with time of the check, e.g.
+// "15:04:05 introduced bar -> baz -> bar". Output is empty if no cycle changed.
+func WatchToTxt(report *model.BaselineReport, now time.Time) string {
+	clock := now.Format("15:04:05")
+	lines := make([]string, 0, len(report.New)+len(report.Fixed))
+	for _, cycle := range report.New {
+		lines = append(lines, fmt.Sprintf("%s introduced %s", clock, strings.Join(cycle, " -> ")))
+	}
+	for _, cycle := range report.Fixed {
+		lines = append(lines, fmt.Sprintf("%s resolved %s", clock, strings.Join(cycle, " -> ")))
+	}
+	return strings.Join(lines, "\n")
+}
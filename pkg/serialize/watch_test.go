@@ -0,0 +1,34 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package serialize
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchToTxt(t *testing.T) {
+	report := &model.BaselineReport{
+		New:      [][]string{{"bar", "foo", "bar"}},
+		Existing: [][]string{{"lib", "util", "lib"}},
+		Fixed:    [][]string{{"bar", "baz", "bar"}},
+	}
+	now := time.Date(2018, 1, 1, 15, 4, 5, 0, time.UTC)
+
+	expected := "15:04:05 introduced bar -> foo -> bar\n15:04:05 resolved bar -> baz -> bar"
+	assert.Equal(t, expected, WatchToTxt(report, now))
+}
+
+func TestWatchToTxt_WithoutChanges(t *testing.T) {
+	report := &model.BaselineReport{
+		New:      [][]string{},
+		Existing: [][]string{{"lib", "util", "lib"}},
+		Fixed:    [][]string{},
+	}
+	assert.Equal(t, "", WatchToTxt(report, time.Now()))
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnticycleAllowlist(t *testing.T) {
+	scenario := testScenario{
+		name:     "Allowlist scenario",
+		testdata: "allowlist",
+	}
+	tests := []testCase{
+		{
+			name:   "%s allowed cycles in text format",
+			args:   []string{"-format=text", "-today=2018-01-01"},
+			golden: "allowlist.txt.golden",
+		},
+		{
+			name:   "%s allowed cycles in JSON format",
+			args:   []string{"-format=json", "-today=2018-01-01"},
+			golden: "allowlist.json.golden",
+			isJSON: true,
+		},
+	}
+
+	for _, test := range tests {
+		runTestGolden(t, scenario, test)
+	}
+}
+
+func TestAnticycleAllowlist_WithExpiredCycles(t *testing.T) {
+	golden := filepath.Join("testdata", "allowlist", "expired.txt.golden")
+	config := filepath.Join("testdata", "allowlist", "expired.json")
+
+	var stdOut, stdErr bytes.Buffer
+	cmd := exec.Command("anticycle", "-config="+config, "-today=2018-01-01", "testdata/allowlist")
+	cmd.Stdout = &stdOut
+	cmd.Stderr = &stdErr
+	err := cmd.Run()
+
+	assert.Error(t, err)
+	assert.Equal(t, "found 1 expired and 1 not allowed cycles, see allowedCycles in '"+config+"'\n", stdErr.String())
+	if *update {
+		updateGolden(golden, stdOut.Bytes())
+	}
+	assert.Equal(t, string(readGolden(golden)), stdOut.String())
+}
+
+func TestAnticycleAllowlist_ShouldExpireByCurrentDate(t *testing.T) {
+	// SOURCE_DATE_EPOCH of the tests is in 2018, but expiry dates are compared with the current date.
+	stdErr, err := exec.Command("anticycle", "-format=json", "testdata/allowlist").CombinedOutput()
+	assert.Error(t, err)
+	assert.Contains(t, string(stdErr),
+		"found 3 expired and 0 not allowed cycles, see allowedCycles in 'testdata/allowlist/.anticycle.json'\n")
+}
+
+func TestAnticycleAllowlist_WithInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name, expected string
+		args           []string
+	}{
+		{
+			name:     "Missing configuration",
+			args:     []string{"-config=testdata/allowlist/missing.json", "testdata/allowlist"},
+			expected: "open testdata/allowlist/missing.json: no such file or directory\n",
+		},
+		{
+			name:     "Configuration which is not JSON",
+			args:     []string{"-config=testdata/allowlist/README.md", "testdata/allowlist"},
+			expected: "-config='testdata/allowlist/README.md' is not a valid configuration: invalid character '#' looking for beginning of value\n",
+		},
+		{
+			name:     "Date which is not valid",
+			args:     []string{"-today=2018-1-1", "testdata/allowlist"},
+			expected: "-today='2018-1-1' is not a valid date, try e.g. '2018-06-01'\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdErr, err := exec.Command("anticycle", test.args...).CombinedOutput()
+			assert.Error(t, err)
+			assert.Equal(t, test.expected, string(stdErr))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/anticycle/anticycle/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnticycleCache(t *testing.T) {
+	cacheDir := filepath.Join(cacheHome, "anticycle")
+	golden := string(readGolden("testdata/onetoone/sanity.txt.golden"))
+
+	stdOut, err := exec.Command("anticycle", "cache", "clean").Output()
+	assert.NoError(t, err)
+	assert.Equal(t, "Removed cache in "+cacheDir+"\n", string(stdOut))
+
+	for _, run := range []string{"cold", "warm"} {
+		stdOut, err := exec.Command("anticycle", "testdata/onetoone").Output()
+		assert.NoError(t, err, run)
+		assert.Equal(t, golden, string(stdOut), run)
+	}
+	caches, err := filepath.Glob(filepath.Join(cacheDir, "*", "*.gob"))
+	assert.NoError(t, err)
+	assert.Len(t, caches, 1)
+	assert.Regexp(t, fmt.Sprintf("-v%d$", model.SchemaVersion), filepath.Dir(caches[0]))
+
+	stdOut, err = exec.Command("anticycle", "cache", "clean").Output()
+	assert.NoError(t, err)
+	assert.Equal(t, "Removed cache in "+cacheDir+"\n", string(stdOut))
+	_, err = os.Stat(cacheDir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestAnticycleCache_WhenDisabled(t *testing.T) {
+	cacheDir := filepath.Join(cacheHome, "anticycle")
+	err := os.RemoveAll(cacheDir)
+	assert.NoError(t, err)
+
+	stdOut, err := exec.Command("anticycle", "-cache=off", "testdata/onetoone").Output()
+	assert.NoError(t, err)
+	assert.Equal(t, string(readGolden("testdata/onetoone/sanity.txt.golden")), string(stdOut))
+	_, err = os.Stat(cacheDir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestAnticycleCache_WithWrongArguments(t *testing.T) {
+	tests := []struct {
+		name, expected string
+		args           []string
+	}{
+		{
+			name:     "Unknown cache option",
+			args:     []string{"-cache=maybe", "testdata/onetoone"},
+			expected: "-cache='maybe' is not available, try one of 'on', 'off'\n",
+		},
+		{
+			name:     "Cache command without subcommand",
+			args:     []string{"cache"},
+			expected: "cache requires 'clean' subcommand\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdErr, err := exec.Command("anticycle", test.args...).CombinedOutput()
+			assert.Error(t, err)
+			assert.Equal(t, test.expected, string(stdErr))
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestAnticycleCIFormats(t *testing.T) {
+	scenarios := []testScenario{
+		{
+			name:     "One to One scenario",
+			testdata: "onetoone",
+		},
+		{
+			name:     "Triangle scenario",
+			testdata: "triangle",
+		},
+	}
+	tests := []testCase{
+		{
+			name:   "%s in junit format",
+			args:   []string{"-format=junit"},
+			golden: "junit.xml.golden",
+		},
+		{
+			name:   "%s in checkstyle format",
+			args:   []string{"-format=checkstyle"},
+			golden: "checkstyle.xml.golden",
+		},
+		{
+			name:   "%s in github format",
+			args:   []string{"-format=github"},
+			golden: "github.golden",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		for _, test := range tests {
+			runTestGolden(t, scenario, test)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestAnticycleDiagrams(t *testing.T) {
+	scenarios := []testScenario{
+		{
+			name:     "One to One scenario",
+			testdata: "onetoone",
+		},
+		{
+			name:     "Triangle scenario",
+			testdata: "triangle",
+		},
+	}
+	tests := []testCase{
+		{
+			name:   "%s cycles in mermaid format",
+			args:   []string{"-format=mermaid"},
+			golden: "diagram.mermaid.golden",
+		},
+		{
+			name:   "%s all packages in mermaid format",
+			args:   []string{"-all", "-format=mermaid"},
+			golden: "diagram-all.mermaid.golden",
+		},
+		{
+			name:   "%s cycles in plantuml format",
+			args:   []string{"-format=plantuml"},
+			golden: "diagram.plantuml.golden",
+		},
+		{
+			name:   "%s all packages in plantuml format",
+			args:   []string{"-all", "-format=plantuml"},
+			golden: "diagram-all.plantuml.golden",
+		},
+		{
+			name:   "%s all packages in html format",
+			args:   []string{"-all", "-format=html"},
+			golden: "report-all.html.golden",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		for _, test := range tests {
+			runTestGolden(t, scenario, test)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnticycleDiff(t *testing.T) {
+	withoutFoo := "testdata/diagonal/all-but-foo.json.golden"
+	withFoo := "testdata/diagonal/all.json.golden"
+	tests := []struct {
+		name, golden string
+		args         []string
+	}{
+		{
+			name:   "Cycle added in text format",
+			args:   []string{withoutFoo, withFoo},
+			golden: "diff.txt.golden",
+		},
+		{
+			name:   "Cycle removed in text format",
+			args:   []string{withFoo, withoutFoo},
+			golden: "diff-reverse.txt.golden",
+		},
+		{
+			name:   "Cycle added in json format",
+			args:   []string{"-format=json", withoutFoo, withFoo},
+			golden: "diff.json.golden",
+		},
+		{
+			name:   "Cycle added in markdown format",
+			args:   []string{"-format=markdown", withoutFoo, withFoo},
+			golden: "diff.md.golden",
+		},
+		{
+			name:   "The same analysis in text format",
+			args:   []string{withFoo, withFoo},
+			golden: "diff-same.txt.golden",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdOut, err := exec.Command("anticycle", append([]string{"diff"}, test.args...)...).Output()
+			assert.NoError(t, err)
+
+			goldenFile := filepath.Join("testdata", "diagonal", test.golden)
+			if *update {
+				updateGolden(goldenFile, stdOut)
+			}
+			assert.Equal(t, string(readGolden(goldenFile)), string(stdOut))
+		})
+	}
+}
+
+func TestAnticycleDiff_WithWrongArguments(t *testing.T) {
+	tests := []struct {
+		name, expected string
+		args           []string
+	}{
+		{
+			name:     "Without paths",
+			args:     []string{"diff"},
+			expected: "diff requires paths to old and new JSON output of anticycle\n",
+		},
+		{
+			name:     "With not supported format",
+			args:     []string{"diff", "-format=html", "old.json", "new.json"},
+			expected: "-format='html' is not available for diff, try one of 'text', 'json', 'markdown'\n",
+		},
+		{
+			name:     "With missing file",
+			args:     []string{"diff", "testdata/diagonal/all.json.golden", "testdata/diagonal/missing.json"},
+			expected: "'testdata/diagonal/missing.json' is not a JSON output of anticycle: open testdata/diagonal/missing.json: no such file or directory\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdErr, err := exec.Command("anticycle", test.args...).CombinedOutput()
+			assert.Error(t, err)
+			assert.Equal(t, test.expected, string(stdErr))
+		})
+	}
+}
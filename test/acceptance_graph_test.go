@@ -0,0 +1,45 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestAnticycleGraphExports(t *testing.T) {
+	scenarios := []testScenario{
+		{
+			name:     "One to One scenario",
+			testdata: "onetoone",
+		},
+		{
+			name:     "Diagonal scenario",
+			testdata: "diagonal",
+		},
+	}
+	tests := []testCase{
+		{
+			name:   "%s in csv format",
+			args:   []string{"-format=csv"},
+			golden: "graph.csv.golden",
+		},
+		{
+			name:   "%s in graphml format",
+			args:   []string{"-format=graphml"},
+			golden: "graph.graphml.golden",
+		},
+		{
+			name:   "%s in gexf format",
+			args:   []string{"-format=gexf"},
+			golden: "graph.gexf.golden",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		for _, test := range tests {
+			runTestGolden(t, scenario, test)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnticycleKinds(t *testing.T) {
+	scenario := testScenario{
+		name:     "Visibility scenario",
+		testdata: "visibility",
+	}
+	tests := []testCase{
+		{
+			name:   "%s all kinds in JSON format",
+			args:   []string{"-all", "-format=json"},
+			golden: "kinds-all.json.golden",
+			isJSON: true,
+		},
+		{
+			name:   "%s only stdlib in text format",
+			args:   []string{"-all", "-kinds=stdlib", "-format=text"},
+			golden: "kinds-stdlib.txt.golden",
+		},
+		{
+			name:   "%s only module and local in JSON format",
+			args:   []string{"-all", "-kinds='module local'", "-format=json"},
+			golden: "kinds-module-local.json.golden",
+			isJSON: true,
+		},
+	}
+
+	for _, test := range tests {
+		runTestGolden(t, scenario, test)
+	}
+}
+
+func TestAnticycleKinds_WithUnknownKind(t *testing.T) {
+	stdErr, err := exec.Command("anticycle", "-kinds=vendor", "./testdata/visibility").CombinedOutput()
+	assert.Error(t, err)
+	assert.Equal(t, "import kind 'vendor' is not available, try one of [stdlib module local external pseudo]\n", string(stdErr))
+}
+
+func TestAnticycleKinds_WithoutGoroot(t *testing.T) {
+	cmd := exec.Command("anticycle", "-cache=off", "testdata/onetoone")
+	cmd.Env = append(os.Environ(), "GOROOT=/tmp/anticycle/not/exist")
+	stdOut, err := cmd.Output()
+	assert.NoError(t, err)
+	assert.Equal(t, string(readGolden("testdata/onetoone/sanity.txt.golden")), string(stdOut))
+}
@@ -0,0 +1,57 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestAnticycleScenarioExternalTest(t *testing.T) {
+	scenario := testScenario{
+		name:     "External test package scenario",
+		testdata: "externalTest",
+	}
+	tests := []testCase{
+		{
+			name:   "%s in text format",
+			args:   []string{"-format=text"},
+			golden: "sanity.txt.golden",
+		},
+		{
+			name:   "%s all packages in JSON format",
+			args:   []string{"-all", "-format=json"},
+			golden: "sanity-all.json.golden",
+			isJSON: true,
+		},
+	}
+
+	for _, test := range tests {
+		runTestGolden(t, scenario, test)
+	}
+}
+
+func TestAnticycleScenarioPackageName(t *testing.T) {
+	scenario := testScenario{
+		name:     "Package name other than directory scenario",
+		testdata: "packageName",
+	}
+	tests := []testCase{
+		{
+			name:   "%s in text format",
+			args:   []string{"-format=text"},
+			golden: "sanity.txt.golden",
+		},
+		{
+			name:   "%s all packages in JSON format",
+			args:   []string{"-all", "-format=json"},
+			golden: "sanity-all.json.golden",
+			isJSON: true,
+		},
+	}
+
+	for _, test := range tests {
+		runTestGolden(t, scenario, test)
+	}
+}
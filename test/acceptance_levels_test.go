@@ -0,0 +1,41 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestAnticycleLevels(t *testing.T) {
+	scenarios := []testScenario{
+		{
+			name:     "Stability scenario",
+			testdata: "stability",
+		},
+		{
+			name:     "Diagonal scenario",
+			testdata: "diagonal",
+		},
+	}
+	tests := []testCase{
+		{
+			name:   "%s levels in text format",
+			args:   []string{"-levels", "-format=text"},
+			golden: "levels.txt.golden",
+		},
+		{
+			name:   "%s levels in JSON format",
+			args:   []string{"-levels", "-format=json"},
+			golden: "levels.json.golden",
+			isJSON: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		for _, test := range tests {
+			runTestGolden(t, scenario, test)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/textproto"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/anticycle/anticycle/internal/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+// lspMessages frames messages with Content-Length headers, like a language client.
+func lspMessages(messages ...string) string {
+	var input bytes.Buffer
+	for _, message := range messages {
+		input.WriteString(fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(message), message))
+	}
+	return input.String()
+}
+
+// readLSPMessages reads all messages written by the language server.
+func readLSPMessages(t *testing.T, output []byte) []map[string]interface{} {
+	messages := make([]map[string]interface{}, 0)
+	reader := textproto.NewReader(bufio.NewReader(bytes.NewReader(output)))
+	for {
+		header, err := reader.ReadMIMEHeader()
+		if err == io.EOF {
+			return messages
+		}
+		assert.NoError(t, err)
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		assert.NoError(t, err)
+		content := make([]byte, length)
+		_, err = io.ReadFull(reader.R, content)
+		assert.NoError(t, err)
+		message := make(map[string]interface{})
+		assert.NoError(t, json.Unmarshal(content, &message))
+		messages = append(messages, message)
+	}
+}
+
+func TestAnticycleLSP(t *testing.T) {
+	root, err := ioutil.TempDir("", "anticycle-lsp")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	assert.NoError(t, fixtures.WriteSource(root, "foo", "package foo"))
+	assert.NoError(t, fixtures.WriteSource(root, "bar", fmt.Sprintf("package bar\n\nimport (\n\t\"fmt\"\n\t\"%s/baz\"\n)", root)))
+	assert.NoError(t, fixtures.WriteSource(root, "baz", fmt.Sprintf("package baz\n\nimport \"%s/bar\"", root)))
+
+	cmd := exec.Command("anticycle", "lsp")
+	cmd.Stdin = bytes.NewBufferString(lspMessages(
+		fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"rootUri":"file://%s"}}`, root),
+		`{"jsonrpc":"2.0","method":"initialized","params":{}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	))
+	output, err := cmd.Output()
+	assert.NoError(t, err)
+
+	messages := readLSPMessages(t, output)
+	if !assert.Len(t, messages, 4) {
+		return
+	}
+	assert.Equal(t, float64(1), messages[0]["id"])
+	assert.Equal(t, map[string]interface{}{
+		"method":  "textDocument/publishDiagnostics",
+		"jsonrpc": "2.0",
+		"params": map[string]interface{}{
+			"uri": "file://" + filepath.Join(root, "bar", "bar.go"),
+			"diagnostics": []interface{}{map[string]interface{}{
+				"range": map[string]interface{}{
+					"start": map[string]interface{}{"line": float64(4), "character": float64(1)},
+					"end":   map[string]interface{}{"line": float64(4), "character": float64(len(root) + 7)},
+				},
+				"severity": float64(1),
+				"source":   "anticycle",
+				"message":  fmt.Sprintf(`import "%s/baz" creates a cycle: bar -> baz -> bar`, root),
+			}},
+		},
+	}, messages[1])
+	assert.Equal(t, "file://"+filepath.Join(root, "baz", "baz.go"), messages[2]["params"].(map[string]interface{})["uri"])
+	assert.Equal(t, map[string]interface{}{"jsonrpc": "2.0", "id": float64(2), "result": nil}, messages[3])
+}
+
+func TestAnticycleLSP_WithoutShutdown(t *testing.T) {
+	cmd := exec.Command("anticycle", "lsp")
+	cmd.Stdin = bytes.NewBufferString(lspMessages(`{"jsonrpc":"2.0","method":"exit"}`))
+	stdErr, err := cmd.StderrPipe()
+	assert.NoError(t, err)
+	assert.NoError(t, cmd.Start())
+	message, _ := ioutil.ReadAll(stdErr)
+	assert.Error(t, cmd.Wait())
+	assert.Equal(t, "exit without shutdown request\n", string(message))
+}
+
+func TestAnticycleLSP_WithWrongKinds(t *testing.T) {
+	stdErr, err := exec.Command("anticycle", "lsp", "-kinds=unknown").CombinedOutput()
+	assert.Error(t, err)
+	assert.Equal(t, "import kind 'unknown' is not available, try one of [stdlib module local external pseudo]\n", string(stdErr))
+}
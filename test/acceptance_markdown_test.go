@@ -0,0 +1,63 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestAnticycleMarkdown(t *testing.T) {
+	scenarios := []testScenario{
+		{
+			name:     "One to One scenario",
+			testdata: "onetoone",
+		},
+		{
+			name:     "Triangle scenario",
+			testdata: "triangle",
+		},
+		{
+			name:     "Empty scenario",
+			testdata: "empty",
+		},
+	}
+	tests := []testCase{
+		{
+			name:   "%s in markdown format",
+			args:   []string{"-format=markdown"},
+			golden: "report.md.golden",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		for _, test := range tests {
+			runTestGolden(t, scenario, test)
+		}
+	}
+}
+
+func TestAnticycleMarkdown_WithBaseline(t *testing.T) {
+	scenario := testScenario{
+		name:     "One to One scenario",
+		testdata: "onetoone",
+	}
+	tests := []testCase{
+		{
+			name:   "%s in markdown format compared with baseline",
+			args:   []string{"-format=markdown", "-baseline=testdata/onetoone/baseline.json"},
+			golden: "report-baseline.md.golden",
+		},
+		{
+			name:   "%s in json format compared with baseline",
+			args:   []string{"-format=json", "-baseline=testdata/onetoone/baseline.json"},
+			golden: "baseline.json.golden",
+			isJSON: true,
+		},
+	}
+
+	for _, test := range tests {
+		runTestGolden(t, scenario, test)
+	}
+}
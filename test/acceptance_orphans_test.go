@@ -0,0 +1,44 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestAnticycleOrphans(t *testing.T) {
+	scenario := testScenario{
+		name:     "Orphans scenario",
+		testdata: "orphans",
+	}
+	tests := []testCase{
+		{
+			name:   "%s orphans in text format",
+			args:   []string{"-orphans", "-format=text"},
+			golden: "orphans.txt.golden",
+		},
+		{
+			name:   "%s orphans in JSON format",
+			args:   []string{"-orphans", "-format=json"},
+			golden: "orphans.json.golden",
+			isJSON: true,
+		},
+		{
+			name:   "%s allowed orphans in text format",
+			args:   []string{"-orphans", "-allowOrphans='lib'", "-format=text"},
+			golden: "orphans-allowed.txt.golden",
+		},
+		{
+			name:   "%s allowed orphans in JSON format",
+			args:   []string{"-orphans", "-allowOrphans='lib'", "-format=json"},
+			golden: "orphans-allowed.json.golden",
+			isJSON: true,
+		},
+	}
+
+	for _, test := range tests {
+		runTestGolden(t, scenario, test)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnticycleOutput_WithManyFormats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anticycle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "report")
+	stdOut, err := exec.Command("anticycle", "-format=text,junit,markdown", "-o="+output, "testdata/onetoone").Output()
+	assert.NoError(t, err)
+	assert.Empty(t, stdOut)
+
+	goldens := map[string]string{
+		"report.txt":       "sanity.txt.golden",
+		"report.junit.xml": "junit.xml.golden",
+		"report.md":        "report.md.golden",
+	}
+	for file, golden := range goldens {
+		result, err := ioutil.ReadFile(filepath.Join(dir, file))
+		assert.NoError(t, err)
+		assert.Equal(t, string(readGolden(filepath.Join("testdata", "onetoone", golden))), string(result), file)
+	}
+}
+
+func TestAnticycleOutput_WithSingleFormatToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anticycle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "report")
+	stdOut, err := exec.Command("anticycle", "render", "-in=testdata/triangle/all.json.golden", "-o="+output).Output()
+	assert.NoError(t, err)
+	assert.Empty(t, stdOut)
+
+	result, err := ioutil.ReadFile(output + ".txt")
+	assert.NoError(t, err)
+	assert.Equal(t, string(readGolden("testdata/triangle/all.txt.golden")), string(result))
+}
+
+func TestAnticycleOutput_WithWrongFormats(t *testing.T) {
+	tests := []struct {
+		name, expected string
+		args           []string
+	}{
+		{
+			name:     "Many formats without output file",
+			args:     []string{"-format=text,json", "testdata/onetoone"},
+			expected: "-format='text,json' has many formats, use -o option to write them into files\n",
+		},
+		{
+			name: "Unknown format in the list",
+			args: []string{"-format=text,yaml", "-o=report", "testdata/onetoone"},
+			expected: "-format='yaml' is not available, try one of 'text', 'json', 'mermaid', 'plantuml', " +
+				"'html', 'junit', 'checkstyle', 'github', 'markdown', 'csv', 'graphml', 'gexf'\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdErr, err := exec.Command("anticycle", test.args...).CombinedOutput()
+			assert.Error(t, err)
+			assert.Equal(t, test.expected, string(stdErr))
+		})
+	}
+}
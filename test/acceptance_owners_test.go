@@ -0,0 +1,78 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnticycleOwners(t *testing.T) {
+	scenario := testScenario{
+		name:     "Owners scenario",
+		testdata: "owners",
+	}
+	tests := []testCase{
+		{
+			name:   "%s owners in text format",
+			args:   []string{"-owners"},
+			golden: "owners.txt.golden",
+		},
+		{
+			name:   "%s owners and teams in text format",
+			args:   []string{"-owners", "-teams"},
+			golden: "teams.txt.golden",
+		},
+		{
+			name:   "%s owners and teams in JSON format",
+			args:   []string{"-teams", "-format=json"},
+			golden: "teams.json.golden",
+			isJSON: true,
+		},
+		{
+			name:   "%s CODEOWNERS given by path",
+			args:   []string{"-owners", "-codeowners=testdata/owners/.github/CODEOWNERS"},
+			golden: "owners.txt.golden",
+		},
+	}
+
+	for _, test := range tests {
+		runTestGolden(t, scenario, test)
+	}
+}
+
+func TestAnticycleOwners_WithWrongArguments(t *testing.T) {
+	tests := []struct {
+		name, expected string
+		args           []string
+	}{
+		{
+			name:     "Without CODEOWNERS file",
+			args:     []string{"-owners", "testdata/onetoone"},
+			expected: "CODEOWNERS file is not found in 'testdata/onetoone', try -codeowners option\n",
+		},
+		{
+			name:     "Missing CODEOWNERS file",
+			args:     []string{"-owners", "-codeowners=testdata/owners/CODEOWNERS", "testdata/owners"},
+			expected: "open testdata/owners/CODEOWNERS: no such file or directory\n",
+		},
+		{
+			name: "Not valid CODEOWNERS file",
+			args: []string{"-teams", "-codeowners=testdata/owners/broken", "testdata/owners"},
+			expected: "-codeowners='testdata/owners/broken' is not valid: " +
+				"CODEOWNERS line 2: pattern '!/api/' is not supported, negation does not work in CODEOWNERS\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdErr, err := exec.Command("anticycle", test.args...).CombinedOutput()
+			assert.Error(t, err)
+			assert.Equal(t, test.expected, string(stdErr))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnticycleRender(t *testing.T) {
+	scenarios := []string{"onetoone", "triangle", "diagonal"}
+	tests := []struct {
+		name, json, golden string
+		args               []string
+	}{
+		{
+			name:   "%s all packages rendered as text",
+			json:   "all.json.golden",
+			golden: "all.txt.golden",
+			args:   []string{"-format=text"},
+		},
+		{
+			name:   "%s cycles rendered as text",
+			json:   "without-all.json.golden",
+			golden: "without-all.txt.golden",
+			args:   []string{"-format=text"},
+		},
+		{
+			name:   "%s all packages rendered as json",
+			json:   "all.json.golden",
+			golden: "all.json.golden",
+			args:   []string{"-format=json"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		for _, test := range tests {
+			t.Run(fmt.Sprintf(test.name, scenario), func(t *testing.T) {
+				input := filepath.Join("testdata", scenario, test.json)
+				args := append([]string{"render", "-in=" + input}, test.args...)
+				stdOut, err := exec.Command("anticycle", args...).Output()
+				assert.NoError(t, err)
+
+				golden := filepath.Join("testdata", scenario, test.golden)
+				assert.Equal(t, string(readGolden(golden)), string(stdOut))
+			})
+		}
+	}
+}
+
+func TestAnticycleRender_FromStdin(t *testing.T) {
+	cmd := exec.Command("anticycle", "render", "-in=-", "-format=text")
+	cmd.Stdin = strings.NewReader(string(readGolden("testdata/onetoone/without-all.json.golden")))
+	stdOut, err := cmd.Output()
+	assert.NoError(t, err)
+	assert.Equal(t, string(readGolden("testdata/onetoone/without-all.txt.golden")), string(stdOut))
+}
+
+func TestAnticycleRender_WithoutInput(t *testing.T) {
+	stdErr, err := exec.Command("anticycle", "render").CombinedOutput()
+	assert.Error(t, err)
+	assert.Equal(t, "render requires -in option with path to JSON output of anticycle\n", string(stdErr))
+}
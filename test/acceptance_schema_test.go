@@ -0,0 +1,45 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnticycleSchema_ShouldMatchPublishedSchema(t *testing.T) {
+	stdOut, err := exec.Command("anticycle", "-schema").Output()
+	assert.NoError(t, err)
+
+	published := readGolden("../schema/anticycle.schema.json")
+	assert.Equal(t, string(published), string(stdOut), "run `make schema` to update published schema")
+}
+
+func TestAnticycleJSON_ShouldDescribeRun(t *testing.T) {
+	stdOut, err := exec.Command("anticycle", "-format=json", "-kinds=module", "testdata/empty").Output()
+	assert.NoError(t, err)
+
+	var output map[string]interface{}
+	err = json.Unmarshal(stdOut, &output)
+	assert.NoError(t, err)
+
+	assert.Equal(t, float64(6), output["schemaVersion"])
+	assert.Equal(t, "testdata/empty", output["root"])
+	assert.Equal(t, "2018-01-01T00:00:00Z", output["timestamp"])
+	assert.Equal(t, "anticycle", output["tool"].(map[string]interface{})["name"])
+	assert.Equal(t, []interface{}{"module"}, output["options"].(map[string]interface{})["kinds"])
+	assert.Equal(t, []interface{}{}, output["cycles"])
+}
+
+func TestAnticycleJSON_WithInvalidSourceDateEpoch(t *testing.T) {
+	cmd := exec.Command("anticycle", "-format=json", "testdata/empty")
+	cmd.Env = append(cmd.Env, "SOURCE_DATE_EPOCH=yesterday")
+	stdErr, err := cmd.CombinedOutput()
+	assert.Error(t, err)
+	assert.Equal(t, "SOURCE_DATE_EPOCH='yesterday' is not a number of seconds\n", string(stdErr))
+}
@@ -0,0 +1,74 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnticycleServe(t *testing.T) {
+	cmd := exec.Command("anticycle", "serve", "-addr=127.0.0.1:0", "testdata/onetoone")
+	stdOut, err := cmd.StdoutPipe()
+	assert.NoError(t, err)
+	assert.NoError(t, cmd.Start())
+	defer cmd.Process.Kill()
+
+	line, err := bufio.NewReader(stdOut).ReadString('\n')
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(line, "Serving analysis of testdata/onetoone on http://127.0.0.1:"), line)
+	url := strings.TrimSpace(line[strings.Index(line, "http://"):])
+
+	resp, err := http.Get(url + "/why?from=foo&to=bar")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Get(url + "/cycles")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	analysis := make(map[string]interface{})
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&analysis))
+	assert.Equal(t, map[string]interface{}{
+		"cycles": []interface{}{
+			[]interface{}{"bar", "baz", "bar"},
+			[]interface{}{"baz", "bar", "baz"},
+		},
+	}, analysis["metadata"])
+}
+
+func TestAnticycleServe_WithWrongArguments(t *testing.T) {
+	tests := []struct {
+		name, expected string
+		args           []string
+	}{
+		{
+			name:     "Unknown import kind",
+			args:     []string{"serve", "-kinds=unknown", "testdata/onetoone"},
+			expected: "import kind 'unknown' is not available, try one of [stdlib module local external pseudo]\n",
+		},
+		{
+			name:     "Wrong address",
+			args:     []string{"serve", "-addr=127.0.0.1:http-alt-x", "testdata/onetoone"},
+			expected: "-addr='127.0.0.1:http-alt-x' can not be used: listen tcp: ",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdErr, err := exec.Command("anticycle", test.args...).CombinedOutput()
+			assert.Error(t, err)
+			// Errors of the network stack differ between systems, so only the beginning is compared.
+			assert.True(t, strings.HasPrefix(string(stdErr), test.expected), string(stdErr))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnticycleSimulate(t *testing.T) {
+	tests := []struct {
+		name, golden string
+		args         []string
+	}{
+		{
+			name:   "Removed import resolves cycle",
+			args:   []string{"-edit=remove bar/bar.go testdata/onetoone/baz"},
+			golden: "simulate-remove.txt.golden",
+		},
+		{
+			name:   "Moved file does not change cycles",
+			args:   []string{"-edit=move foo/foo.go qux"},
+			golden: "simulate-move.txt.golden",
+		},
+		{
+			name: "Added import introduces cycle in json format",
+			args: []string{"-format=json", "-edit=add foo/foo.go testdata/onetoone/baz",
+				"-edit=remove baz/baz.go testdata/onetoone/bar"},
+			golden: "simulate-add.json.golden",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args := append(append([]string{"simulate"}, test.args...), "testdata/onetoone")
+			stdOut, err := exec.Command("anticycle", args...).Output()
+			assert.NoError(t, err)
+
+			goldenFile := filepath.Join("testdata", "onetoone", test.golden)
+			if *update {
+				updateGolden(goldenFile, stdOut)
+			}
+			assert.Equal(t, string(readGolden(goldenFile)), string(stdOut))
+		})
+	}
+}
+
+func TestAnticycleSimulate_WithWrongArguments(t *testing.T) {
+	tests := []struct {
+		name, expected string
+		args           []string
+	}{
+		{
+			name:     "Without edits",
+			args:     []string{"simulate", "testdata/onetoone"},
+			expected: "simulate requires at least one -edit option, e.g. -edit='move models/errors.go errs'\n",
+		},
+		{
+			name:     "Wrong edit",
+			args:     []string{"simulate", "-edit=rename foo/foo.go", "testdata/onetoone"},
+			expected: "edit 'rename foo/foo.go' is not valid, try 'move FILE DIR', 'remove FILE IMPORT' or 'add FILE IMPORT'\n",
+		},
+		{
+			name:     "Unknown file",
+			args:     []string{"simulate", "-edit=move foo/qux.go qux", "testdata/onetoone"},
+			expected: "file 'foo/qux.go' is not found\n",
+		},
+		{
+			name:     "Wrong format",
+			args:     []string{"simulate", "-format=html", "-edit=move foo/foo.go qux", "testdata/onetoone"},
+			expected: "-format='html' is not available for simulate, try one of 'text', 'json'\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdErr, err := exec.Command("anticycle", test.args...).CombinedOutput()
+			assert.Error(t, err)
+			assert.Equal(t, test.expected, string(stdErr))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnticycleSplit(t *testing.T) {
+	tests := []struct {
+		name, golden string
+		args         []string
+	}{
+		{
+			name:   "Split in text format",
+			args:   []string{"-pkg=models"},
+			golden: "split.txt.golden",
+		},
+		{
+			name:   "Split in json format",
+			args:   []string{"-pkg=models", "-format=json"},
+			golden: "split.json.golden",
+		},
+		{
+			name:   "Package which can not be split",
+			args:   []string{"-pkg=db"},
+			golden: "split-db.txt.golden",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args := append(append([]string{"split"}, test.args...), "testdata/split")
+			stdOut, err := exec.Command("anticycle", args...).Output()
+			assert.NoError(t, err)
+
+			goldenFile := filepath.Join("testdata", "split", test.golden)
+			if *update {
+				updateGolden(goldenFile, stdOut)
+			}
+			assert.Equal(t, string(readGolden(goldenFile)), string(stdOut))
+		})
+	}
+}
+
+func TestAnticycleSplit_WithWrongArguments(t *testing.T) {
+	tests := []struct {
+		name, expected string
+		args           []string
+	}{
+		{
+			name:     "Without package",
+			args:     []string{"split", "testdata/split"},
+			expected: "split requires -pkg option with name or path of a package, e.g. -pkg=models\n",
+		},
+		{
+			name:     "Unknown package",
+			args:     []string{"split", "-pkg=qux", "testdata/split"},
+			expected: "package 'qux' is not found\n",
+		},
+		{
+			name:     "Wrong format",
+			args:     []string{"split", "-pkg=models", "-format=html", "testdata/split"},
+			expected: "-format='html' is not available for split, try one of 'text', 'json'\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdErr, err := exec.Command("anticycle", test.args...).CombinedOutput()
+			assert.Error(t, err)
+			assert.Equal(t, test.expected, string(stdErr))
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestAnticycleStability(t *testing.T) {
+	scenarios := []testScenario{
+		{
+			name:     "Stability scenario",
+			testdata: "stability",
+		},
+		{
+			name:     "One to One scenario",
+			testdata: "onetoone",
+		},
+	}
+	tests := []testCase{
+		{
+			name:   "%s stability in text format",
+			args:   []string{"-stability", "-format=text"},
+			golden: "stability.txt.golden",
+		},
+		{
+			name:   "%s stability in JSON format",
+			args:   []string{"-stability", "-format=json"},
+			golden: "stability.json.golden",
+			isJSON: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		for _, test := range tests {
+			runTestGolden(t, scenario, test)
+		}
+	}
+}
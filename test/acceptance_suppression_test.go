@@ -0,0 +1,38 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestAnticycleSuppression(t *testing.T) {
+	scenario := testScenario{
+		name:     "Suppression scenario",
+		testdata: "suppression",
+	}
+	tests := []testCase{
+		{
+			name:   "%s suppressions in text format",
+			args:   []string{"-format=text"},
+			golden: "suppression.txt.golden",
+		},
+		{
+			name:   "%s suppressions in JSON format",
+			args:   []string{"-format=json"},
+			golden: "suppression.json.golden",
+			isJSON: true,
+		},
+		{
+			name:   "%s all packages in text format",
+			args:   []string{"-all", "-format=text"},
+			golden: "suppression-all.txt.golden",
+		},
+	}
+
+	for _, test := range tests {
+		runTestGolden(t, scenario, test)
+	}
+}
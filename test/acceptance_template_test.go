@@ -0,0 +1,112 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const summaryTemplate = "testdata/templates/summary.md.tmpl"
+
+func TestAnticycleTemplate(t *testing.T) {
+	tests := []struct {
+		name, golden string
+		args         []string
+	}{
+		{
+			name:   "Template of onetoone",
+			args:   []string{"-template=" + summaryTemplate, "testdata/onetoone"},
+			golden: "testdata/onetoone/summary.md.golden",
+		},
+		{
+			name:   "Template of triangle",
+			args:   []string{"-template=" + summaryTemplate, "testdata/triangle"},
+			golden: "testdata/triangle/summary.md.golden",
+		},
+		{
+			name:   "Template of saved analysis",
+			args:   []string{"render", "-in=testdata/triangle/all.json.golden", "-template=" + summaryTemplate},
+			golden: "testdata/triangle/summary.md.golden",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := exec.Command("anticycle", test.args...).CombinedOutput()
+			assert.NoError(t, err, string(result))
+
+			if *update {
+				updateGolden(test.golden, result)
+			}
+			assert.Equal(t, string(readGolden(test.golden)), string(result))
+		})
+	}
+}
+
+func TestAnticycleTemplate_WithFormat(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anticycle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "report")
+	stdOut, err := exec.Command("anticycle", "-format=text", "-template="+summaryTemplate, "-o="+output, "testdata/onetoone").Output()
+	assert.NoError(t, err)
+	assert.Empty(t, stdOut)
+
+	goldens := map[string]string{
+		"report.txt": "sanity.txt.golden",
+		"report.md":  "summary.md.golden",
+	}
+	for file, golden := range goldens {
+		result, err := ioutil.ReadFile(filepath.Join(dir, file))
+		assert.NoError(t, err)
+		assert.Equal(t, string(readGolden(filepath.Join("testdata", "onetoone", golden))), string(result), file)
+	}
+}
+
+func TestAnticycleTemplate_WithWrongTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anticycle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	broken := filepath.Join(dir, "broken.tmpl")
+	err = ioutil.WriteFile(broken, []byte("{{range .Cycles}}"), 0644)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name, expected string
+		args           []string
+	}{
+		{
+			name:     "Missing template",
+			args:     []string{"-template=testdata/templates/missing.tmpl", "testdata/onetoone"},
+			expected: "-template='testdata/templates/missing.tmpl' can not be read: open testdata/templates/missing.tmpl: no such file or directory\n",
+		},
+		{
+			name:     "Template with syntax error",
+			args:     []string{"-template=" + broken, "testdata/onetoone"},
+			expected: "-template='" + broken + "' is not a valid template: template: template:1: unexpected EOF\n",
+		},
+		{
+			name:     "Template with format without output file",
+			args:     []string{"-format=json", "-template=" + summaryTemplate, "testdata/onetoone"},
+			expected: "-format='json' with -template='" + summaryTemplate + "' has many formats, use -o option to write them into files\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdErr, err := exec.Command("anticycle", test.args...).CombinedOutput()
+			assert.Error(t, err)
+			assert.Equal(t, test.expected, string(stdErr))
+		})
+	}
+}
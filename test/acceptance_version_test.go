@@ -6,7 +6,10 @@ package test
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"testing"
 
@@ -88,6 +91,27 @@ func TestAnticycleVersionJSONFormat_ShouldPrintInJSON(t *testing.T) {
 	assert.True(t, validBuild.MatchString(build))
 }
 
+func TestAnticycleVersionManyFormats_ShouldWriteFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anticycle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "version")
+	stdOut, err := exec.Command("anticycle", "-version", "-format=text,json", "-o="+output).Output()
+	assert.NoError(t, err)
+	assert.Empty(t, stdOut)
+
+	text, err := ioutil.ReadFile(output + ".txt")
+	assert.NoError(t, err)
+	assert.Regexp(t, fullVersionRegex, string(text))
+
+	var version map[string]string
+	data, err := ioutil.ReadFile(output + ".json")
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &version))
+	assert.Regexp(t, versionRegex, version["version"])
+}
+
 func TestAnticycleVersionWithHelpFlag_ShouldPrintHelp(t *testing.T) {
 	stdOut, err := exec.Command("anticycle", "-version", "-help").Output()
 	assert.NoError(t, err)
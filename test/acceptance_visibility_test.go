@@ -0,0 +1,33 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"testing"
+)
+
+func TestAnticycleVisibility(t *testing.T) {
+	scenario := testScenario{
+		name:     "Visibility scenario",
+		testdata: "visibility",
+	}
+	tests := []testCase{
+		{
+			name:   "%s visibility in text format",
+			args:   []string{"-visibility", "-format=text"},
+			golden: "visibility.txt.golden",
+		},
+		{
+			name:   "%s visibility in JSON format",
+			args:   []string{"-visibility", "-format=json"},
+			golden: "visibility.json.golden",
+			isJSON: true,
+		},
+	}
+
+	for _, test := range tests {
+		runTestGolden(t, scenario, test)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package test
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/anticycle/anticycle/internal/pkg/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+var clockPrefix = regexp.MustCompile(`^\d\d:\d\d:\d\d `)
+
+// nextLine waits for the next line of output without time prefix.
+func nextLine(t *testing.T, lines <-chan string) string {
+	select {
+	case line := <-lines:
+		return clockPrefix.ReplaceAllString(line, "")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout while waiting for output of -watch")
+	}
+	return ""
+}
+
+func TestAnticycleWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anticycle-watch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, fixtures.WriteSource(dir, "foo", "package foo"))
+	assert.NoError(t, fixtures.WriteSource(dir, "bar", fmt.Sprintf("package bar\nimport \"%s/baz\"", dir)))
+	assert.NoError(t, fixtures.WriteSource(dir, "baz", fmt.Sprintf("package baz\nimport \"%s/bar\"", dir)))
+
+	cmd := exec.Command("anticycle", "-watch", "-interval=50ms", dir)
+	stdOut, err := cmd.StdoutPipe()
+	assert.NoError(t, err)
+	assert.NoError(t, cmd.Start())
+	defer cmd.Process.Kill()
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(stdOut)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	assert.Equal(t, fmt.Sprintf("Watching %s, found 1 cycles", dir), nextLine(t, lines))
+	assert.Equal(t, "bar -> baz -> bar", nextLine(t, lines))
+
+	assert.NoError(t, fixtures.WriteSource(dir, "qux", fmt.Sprintf("package qux\nimport \"%s/foo\"", dir)))
+	assert.NoError(t, fixtures.WriteSource(dir, "foo", fmt.Sprintf("package foo\nimport \"%s/qux\"", dir)))
+	assert.Equal(t, "introduced foo -> qux -> foo", nextLine(t, lines))
+
+	assert.NoError(t, fixtures.WriteSource(dir, "baz", "package baz"))
+	assert.Equal(t, "resolved bar -> baz -> bar", nextLine(t, lines))
+}
+
+func TestAnticycleWatch_WithWrongInterval(t *testing.T) {
+	stdErr, err := exec.Command("anticycle", "-watch", "-interval=often", "testdata/onetoone").CombinedOutput()
+	assert.Error(t, err)
+	assert.Equal(t, "-interval='often' is not a valid duration, try e.g. '500ms' or '2s'\n", string(stdErr))
+}
@@ -9,6 +9,7 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"testing"
@@ -30,6 +31,21 @@ type (
 // Update flag for sanity and acceptance tests
 var update = flag.Bool("update", false, "update .golden files")
 
+// Fixed timestamp of JSON output, inherited by anticycle binary run by tests.
+const sourceDateEpoch = "1514764800"
+
+// Cache of parsed files is kept in temporary directory instead of user cache directory.
+var cacheHome = filepath.Join(os.TempDir(), "anticycle-test-cache")
+
+func init() {
+	if err := os.Setenv("SOURCE_DATE_EPOCH", sourceDateEpoch); err != nil {
+		panic(err)
+	}
+	if err := os.Setenv("XDG_CACHE_HOME", cacheHome); err != nil {
+		panic(err)
+	}
+}
+
 func updateGolden(filename string, data []byte) {
 	err := ioutil.WriteFile(filename, data, 0644)
 	if err != nil {
@@ -68,6 +84,9 @@ func runTestGolden(t *testing.T, scenario testScenario, test testCase) {
 				panic(err)
 			}
 
+			// Tool version and build differ between builds, so they are not compared.
+			delete(expected, "tool")
+			delete(result, "tool")
 			assert.Equal(t, expected, result)
 		} else {
 			expected := readGolden(goldenFile)
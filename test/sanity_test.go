@@ -130,6 +130,9 @@ func TestAnticycle(t *testing.T) {
 					panic(err)
 				}
 
+				// Tool version and build differ between builds, so they are not compared.
+				delete(expected, "tool")
+				delete(result, "tool")
 				assert.Equal(t, expected, result)
 			} else {
 				expected := readGolden(test.golden)
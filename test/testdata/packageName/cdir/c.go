@@ -0,0 +1,11 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package c
+
+import (
+	"github.com/anticycle/anticycle/test/testdata/packageName/bdir"
+)
+
+var C = b.B
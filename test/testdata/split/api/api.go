@@ -0,0 +1,19 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"testdata/split/models"
+)
+
+// Format formats the name.
+func Format(name string) string {
+	return name
+}
+
+// Load loads the user.
+func Load() models.User {
+	return models.User{}
+}
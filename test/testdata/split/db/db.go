@@ -0,0 +1,15 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testdata/split/models"
+)
+
+// Save saves the name.
+func Save(name string) error {
+	models.Handle()
+	return nil
+}
@@ -0,0 +1,10 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package models
+
+// Handle renders an anonymous user.
+func Handle() string {
+	return Render(User{})
+}
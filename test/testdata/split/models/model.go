@@ -0,0 +1,10 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package models
+
+// User is a user of the application.
+type User struct {
+	Name string
+}
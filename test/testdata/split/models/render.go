@@ -0,0 +1,14 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testdata/split/api"
+)
+
+// Render formats name of the user for the api.
+func Render(user User) string {
+	return api.Format(user.Name)
+}
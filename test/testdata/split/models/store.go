@@ -0,0 +1,14 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testdata/split/db"
+)
+
+// Store saves the user.
+func Store(user User) error {
+	return db.Save(user.Name)
+}
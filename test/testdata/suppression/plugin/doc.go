@@ -0,0 +1,8 @@
+// Copyright 2018 The Anticycle Authors. All rights reserved.
+// Use of this source code is governed by a GPL-style
+// license that can be found in the LICENSE file.
+
+// Package plugin holds plugins which register themselves in core.
+//anticycle:allow-cycle with=suppression/core plugins register themselves
+//anticycle:allow-cycle with=suppression/legacy removed in the last release
+package plugin